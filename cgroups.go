@@ -0,0 +1,323 @@
+// File that describes how the cgroups of the processes are interpreted and
+// how the usage and limits of every cgroup are read.
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Length container IDs are shortened to, like docker ps does.
+const containerIDLength = 12
+
+// containerRuntimes are the prefixes of the systemd scopes, or of the
+// directories, container runtimes create for each container, with the name
+// of the runtime shown in the container column.
+var containerRuntimes = []struct {
+	prefix  string
+	runtime string
+}{
+	{"docker-", "docker"},
+	{"libpod-", "podman"},
+	{"crio-", "crio"},
+	{"cri-containerd-", "containerd"},
+}
+
+// cgroupLabel derives what the cgroup path of a process belongs to, from the
+// most to the least specific:
+// * pod:UID for the processes of a Kubernetes pod.
+// * runtime:ID for the processes of a Docker, Podman, CRI-O or containerd
+// container, with the ID shortened.
+// * The innermost systemd service or scope, like sshd.service or session-2.scope.
+// * The path itself otherwise, like / for the root cgroup.
+func cgroupLabel(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	if strings.Contains(path, "kubepods") {
+		for _, segment := range segments {
+			if uid, ok := podUID(segment); ok {
+				return "pod:" + uid
+			}
+		}
+	}
+
+	// The innermost container wins, as containers can be nested.
+	for i := len(segments) - 1; i >= 0; i-- {
+		segment := strings.TrimSuffix(segments[i], ".scope")
+		for _, r := range containerRuntimes {
+			if id := strings.TrimPrefix(segment, r.prefix); id != segment && isContainerID(id) {
+				return r.runtime + ":" + id[:containerIDLength]
+			}
+		}
+
+		// The cgroupfs driver names the directory of the container after
+		// its ID, inside one named after the runtime, like /docker/ID.
+		if isContainerID(segment) && i > 0 {
+			return segments[i-1] + ":" + segment[:containerIDLength]
+		}
+	}
+
+	for i := len(segments) - 1; i >= 0; i-- {
+		if strings.HasSuffix(segments[i], ".service") || strings.HasSuffix(segments[i], ".scope") {
+			return segments[i]
+		}
+	}
+
+	return path
+}
+
+// podUID returns the UID of the Kubernetes pod a cgroup directory belongs to.
+// The systemd driver names it like kubepods-burstable-pod<UID>.slice, with
+// the dashes of the UID replaced by underscores, and the cgroupfs driver like
+// pod<UID>.
+func podUID(segment string) (string, bool) {
+	segment = strings.TrimSuffix(segment, ".slice")
+
+	i := strings.LastIndex(segment, "pod")
+	if i < 0 || (i > 0 && segment[i-1] != '-') {
+		return "", false
+	}
+
+	uid := strings.ReplaceAll(segment[i+len("pod"):], "_", "-")
+	if uid == "" {
+		return "", false
+	}
+
+	return uid, true
+}
+
+// isContainerID reports whether the text looks like the ID of a container: a
+// long hexadecimal string.
+func isContainerID(s string) bool {
+	if len(s) < containerIDLength {
+		return false
+	}
+
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// cgroupInfo is the usage and the limits of a cgroup of the unified (v2)
+// hierarchy. Limits are zero when the cgroup has none.
+type cgroupInfo struct {
+	// Path of the cgroup from the root of the hierarchy, / for the root.
+	Path string
+	// Percentage of one core used since the previous collection, from
+	// cpu.stat, and the one allowed by cpu.max.
+	CpuPercentage float64
+	CpuLimit      float64
+	// Memory used by the cgroup and its limit, in bytes.
+	MemoryCurrent uint64
+	MemoryMax     uint64
+	// Bytes per second read from and written to every device since the
+	// previous collection, from io.stat.
+	IOReadRate  float64
+	IOWriteRate float64
+	// Amount of processes in the cgroup and its limit.
+	PidsCurrent uint64
+	PidsMax     uint64
+}
+
+// cgroupUsage is the accumulated usage of a cgroup, used to calculate its
+// rates between two collections.
+type cgroupUsage struct {
+	// CPU time in microseconds.
+	cpuTime    uint64
+	readBytes  uint64
+	writeBytes uint64
+}
+
+// cgroupCollector gathers the usage and limits of every cgroup by walking a
+// cgroup v2 mount.
+type cgroupCollector struct {
+	// Path to the cgroup v2 mount. It is "/sys/fs/cgroup" unless another
+	// hierarchy is given, like a fixture directory.
+	root string
+	// Usage of each cgroup, indexed by its path, up to the previous
+	// collection and when it happened.
+	prevUsage map[string]cgroupUsage
+	prevTime  time.Time
+}
+
+// newCgroupCollector returns a collector reading from the cgroup v2
+// hierarchy mounted in root.
+func newCgroupCollector(root string) *cgroupCollector {
+	return &cgroupCollector{root: root}
+}
+
+// reset forgets the previous collection, so the next one has no rates
+// instead of rates averaged over the time the cgroups weren't collected.
+func (c *cgroupCollector) reset() {
+	c.prevUsage = nil
+	c.prevTime = time.Time{}
+}
+
+// collect walks the hierarchy and returns the information of each cgroup,
+// sorted by path. The files missing in a cgroup, as its controller isn't
+// enabled, leave their values empty. It fails when root isn't a cgroup v2
+// mount.
+func (c *cgroupCollector) collect() ([]cgroupInfo, error) {
+	if _, err := os.Stat(filepath.Join(c.root, "cgroup.controllers")); err != nil {
+		return nil, fmt.Errorf("no cgroup v2 hierarchy in %s", c.root)
+	}
+
+	now := time.Now()
+	elapsed := now.Sub(c.prevTime).Seconds()
+	usages := make(map[string]cgroupUsage, len(c.prevUsage))
+
+	var cgroups []cgroupInfo
+	err := filepath.WalkDir(c.root, func(dir string, entry fs.DirEntry, err error) error {
+		// Cgroups removed while walking are skipped.
+		if err != nil || !entry.IsDir() {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(c.root, dir)
+		if rel == "." {
+			rel = ""
+		}
+		info := cgroupInfo{Path: "/" + filepath.ToSlash(rel)}
+
+		var usage cgroupUsage
+		usage.cpuTime = readKeyedValues(filepath.Join(dir, "cpu.stat"))["usage_usec"]
+		usage.readBytes, usage.writeBytes = readIOStat(filepath.Join(dir, "io.stat"))
+		usages[info.Path] = usage
+
+		// The usage of a cgroup not seen before is only known from the
+		// next collection on.
+		if prev, ok := c.prevUsage[info.Path]; ok && elapsed > 0 {
+			info.CpuPercentage = float64(usage.cpuTime-min(prev.cpuTime, usage.cpuTime)) / 1e6 / elapsed * 100
+			info.IOReadRate = float64(usage.readBytes-min(prev.readBytes, usage.readBytes)) / elapsed
+			info.IOWriteRate = float64(usage.writeBytes-min(prev.writeBytes, usage.writeBytes)) / elapsed
+		}
+
+		info.CpuLimit = readCpuMax(filepath.Join(dir, "cpu.max"))
+		info.MemoryCurrent, _ = readCgroupValue(filepath.Join(dir, "memory.current"))
+		info.MemoryMax, _ = readCgroupValue(filepath.Join(dir, "memory.max"))
+		info.PidsCurrent, _ = readCgroupValue(filepath.Join(dir, "pids.current"))
+		info.PidsMax, _ = readCgroupValue(filepath.Join(dir, "pids.max"))
+
+		cgroups = append(cgroups, info)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.prevUsage = usages
+	c.prevTime = now
+
+	sort.Slice(cgroups, func(i, j int) bool { return cgroups[i].Path < cgroups[j].Path })
+
+	return cgroups, nil
+}
+
+// readCgroupValue returns the single value of a cgroup interface file, like
+// memory.current. A limit of "max" is returned as zero.
+func readCgroupValue(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	value := strings.TrimSpace(string(data))
+	if value == "max" {
+		return 0, nil
+	}
+
+	return strconv.ParseUint(value, 10, 64)
+}
+
+// readCpuMax returns the percentage of one core allowed by a cpu.max file,
+// or zero when there is no limit.
+func readCpuMax(path string) float64 {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+
+	// $MAX $PERIOD, with $MAX being "max" for no limit.
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 || fields[0] == "max" {
+		return 0
+	}
+
+	quota, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0
+	}
+	period, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil || period == 0 {
+		return 0
+	}
+
+	return quota / period * 100
+}
+
+// readKeyedValues returns the values of a flat keyed file, like cpu.stat,
+// indexed by their key.
+func readKeyedValues(path string) map[string]uint64 {
+	values := make(map[string]uint64)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return values
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		// key value
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+
+		if value, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			values[fields[0]] = value
+		}
+	}
+
+	return values
+}
+
+// readIOStat returns the bytes read and written by a cgroup on every device,
+// from its io.stat file.
+func readIOStat(path string) (uint64, uint64) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, 0
+	}
+
+	var read, written uint64
+	for _, line := range strings.Split(string(data), "\n") {
+		// $MAJ:$MIN rbytes=N wbytes=N rios=N wios=N dbytes=N dios=N
+		fields := strings.Fields(line)
+		for _, field := range fields[min(len(fields), 1):] {
+			key, value, _ := strings.Cut(field, "=")
+			n, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				continue
+			}
+
+			switch key {
+			case "rbytes":
+				read += n
+			case "wbytes":
+				written += n
+			}
+		}
+	}
+
+	return read, written
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fixtureCgroup is a cgroup v2 hierarchy with an unlimited slice, a
+// container limited in CPU, memory and tasks, and a cgroup without any
+// controller enabled.
+const fixtureCgroup = "testdata/cgroup"
+
+// fixtureContainer is the path of the container of fixtureCgroup.
+const fixtureContainer = "/system.slice/docker-0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef.scope"
+
+func TestCgroupCollectorCollect(t *testing.T) {
+	c := newCgroupCollector(fixtureCgroup)
+
+	cgroups, err := c.collect()
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+
+	// The rates are only known from the second collection on.
+	want := []cgroupInfo{
+		{Path: "/", MemoryCurrent: 4294967296, PidsCurrent: 120},
+		// No controller file, so every value is left empty.
+		{Path: "/kubepods.slice"},
+		// Limits of "max" are read as no limit.
+		{Path: "/system.slice", MemoryCurrent: 1073741824, PidsCurrent: 40},
+		{Path: fixtureContainer, CpuLimit: 50, MemoryCurrent: 483183820, MemoryMax: 536870912,
+			PidsCurrent: 12, PidsMax: 100},
+	}
+	if !reflect.DeepEqual(cgroups, want) {
+		t.Errorf("collect =\n%+v\nwant\n%+v", cgroups, want)
+	}
+}
+
+func TestCgroupCollectorRates(t *testing.T) {
+	c := newCgroupCollector(fixtureCgroup)
+	c.prevTime = time.Now().Add(-2 * time.Second)
+	c.prevUsage = map[string]cgroupUsage{
+		fixtureContainer: {cpuTime: 1000000},
+		// Counters that went backwards, as when the cgroup was recreated.
+		"/": {cpuTime: 9000000, readBytes: 5000, writeBytes: 5000},
+	}
+
+	cgroups, err := c.collect()
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+
+	byPath := make(map[string]cgroupInfo)
+	for _, cgroup := range cgroups {
+		byPath[cgroup.Path] = cgroup
+	}
+
+	near := func(got, want float64) bool { return math.Abs(got-want) <= want*0.05 }
+
+	// A second of CPU time and 8 KiB read and written on two devices in
+	// about two seconds.
+	container := byPath[fixtureContainer]
+	if !near(container.CpuPercentage, 50) || !near(container.IOReadRate, 4096) || !near(container.IOWriteRate, 4096) {
+		t.Errorf("container rates = %.1f%% %.0f B/s %.0f B/s, want about 50%% 4096 B/s 4096 B/s",
+			container.CpuPercentage, container.IOReadRate, container.IOWriteRate)
+	}
+
+	if root := byPath["/"]; root.CpuPercentage != 0 || root.IOReadRate != 0 || root.IOWriteRate != 0 {
+		t.Errorf("root rates = %v %v %v, want zero", root.CpuPercentage, root.IOReadRate, root.IOWriteRate)
+	}
+
+	// Cgroups not seen before have no rates yet.
+	if slice := byPath["/system.slice"]; slice.CpuPercentage != 0 {
+		t.Errorf("new cgroup CPU = %v, want zero", slice.CpuPercentage)
+	}
+}
+
+func TestCgroupCollectorReset(t *testing.T) {
+	c := newCgroupCollector(fixtureCgroup)
+	c.prevTime = time.Now().Add(-time.Hour)
+	c.prevUsage = map[string]cgroupUsage{fixtureContainer: {cpuTime: 1000000}}
+	c.reset()
+
+	cgroups, err := c.collect()
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	for _, cgroup := range cgroups {
+		if cgroup.CpuPercentage != 0 || cgroup.IOReadRate != 0 || cgroup.IOWriteRate != 0 {
+			t.Errorf("cgroup %s has rates after a reset, want none", cgroup.Path)
+		}
+	}
+}
+
+func TestCgroupCollectorNoHierarchy(t *testing.T) {
+	if _, err := newCgroupCollector("testdata").collect(); err == nil {
+		t.Error("collect succeeded on a directory without cgroup.controllers, want an error")
+	}
+}
+
+func TestCgroupLabel(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/", "/"},
+		{"/init.scope", "init.scope"},
+		{"/system.slice/sshd.service", "sshd.service"},
+		{"/user.slice/user-1000.slice/user@1000.service/app.slice/session-2.scope", "session-2.scope"},
+		{fixtureContainer, "docker:0123456789ab"},
+		{"/machine.slice/libpod-fedcba9876543210fedcba9876543210.scope/container", "podman:fedcba987654"},
+		{"/docker/0123456789abcdef0123456789abcdef", "docker:0123456789ab"},
+		{"/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod1a2b_3c4d.slice/" +
+			"cri-containerd-0123456789abcdef0123456789abcdef.scope", "pod:1a2b-3c4d"},
+		{"/kubepods/besteffort/pod1a2b-3c4d/0123456789abcdef0123456789abcdef", "pod:1a2b-3c4d"},
+		// Too short to be the ID of a container.
+		{"/docker/abc", "/docker/abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := cgroupLabel(tt.path); got != tt.want {
+				t.Errorf("cgroupLabel(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPodUID(t *testing.T) {
+	tests := []struct {
+		segment string
+		want    string
+		ok      bool
+	}{
+		{"kubepods-besteffort-pod1a2b_3c4d.slice", "1a2b-3c4d", true},
+		{"pod1a2b-3c4d", "1a2b-3c4d", true},
+		{"kubepods.slice", "", false},
+		{"kubepods-burstable.slice", "", false},
+		{"pod", "", false},
+		{"ipod1234", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.segment, func(t *testing.T) {
+			got, ok := podUID(tt.segment)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("podUID(%q) = %q, %v, want %q, %v", tt.segment, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
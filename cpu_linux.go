@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// readCpuTimes returns the time spent by the whole CPU and by each core in
+// each state since the system booted.
+func readCpuTimes() (cpuTimes, []cpuTimes, error) {
+	return processesCollector.readCpuTimes()
+}
+
+// readCpuTimes parses the cpu lines of the stat file in the procfs root. The
+// first one holds the sum of every core and the rest one core each, in
+// clock ticks. Each core is put at the index of its cpuN line, so offline
+// cores, which have no line, are left with empty times instead of shifting
+// the ones after them.
+func (c *procCollector) readCpuTimes() (cpuTimes, []cpuTimes, error) {
+	f, err := os.Open(filepath.Join(c.root, "stat"))
+	if err != nil {
+		return cpuTimes{}, nil, err
+	}
+	defer f.Close()
+
+	var all cpuTimes
+	var cores []cpuTimes
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// cpu user nice system idle iowait irq softirq steal guest guest_nice
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], "cpu") {
+			continue
+		}
+		if len(fields) < 9 {
+			return cpuTimes{}, nil, fmt.Errorf("malformed %s line in the stat file in %s", fields[0], c.root)
+		}
+
+		var values [8]float64
+		for i := range values {
+			ticks, err := strconv.ParseUint(fields[i+1], 10, 64)
+			if err != nil {
+				return cpuTimes{}, nil, err
+			}
+			values[i] = float64(ticks) / clockTicks
+		}
+
+		times := cpuTimes{
+			User:   values[0],
+			Nice:   values[1],
+			System: values[2],
+			Idle:   values[3],
+			IOWait: values[4],
+			// Hardware and software interrupts are shown together.
+			Irq:   values[5] + values[6],
+			Steal: values[7],
+		}
+
+		if fields[0] == "cpu" {
+			all = times
+			continue
+		}
+
+		index, err := strconv.Atoi(strings.TrimPrefix(fields[0], "cpu"))
+		if err != nil || index < 0 {
+			return cpuTimes{}, nil, fmt.Errorf("malformed %s line in the stat file in %s", fields[0], c.root)
+		}
+		for len(cores) <= index {
+			cores = append(cores, cpuTimes{})
+		}
+		cores[index] = times
+	}
+
+	if err := scanner.Err(); err != nil {
+		return cpuTimes{}, nil, err
+	}
+
+	return all, cores, nil
+}
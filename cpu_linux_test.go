@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProcCollectorReadCpuTimes(t *testing.T) {
+	root := t.TempDir()
+	// The core 1 is offline, so it has no line.
+	stat := "cpu  400 0 200 1400 0 0 0 0 0 0\n" +
+		"cpu0 200 0 100 700 0 0 0 0 0 0\n" +
+		"cpu2 200 0 100 700 0 0 0 0 0 0\n" +
+		"intr 0\n" +
+		"btime 1700000000\n"
+	if err := os.WriteFile(filepath.Join(root, "stat"), []byte(stat), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	all, cores, err := newProcCollector(root).readCpuTimes()
+	if err != nil {
+		t.Fatalf("readCpuTimes: %v", err)
+	}
+
+	if all.total() != 2000/clockTicks {
+		t.Errorf("total CPU time = %v, want %v", all.total(), 2000/clockTicks)
+	}
+	if len(cores) != 3 {
+		t.Fatalf("readCpuTimes returned %d cores, want 3", len(cores))
+	}
+	if cores[1] != (cpuTimes{}) {
+		t.Errorf("offline core times = %+v, want empty", cores[1])
+	}
+	if cores[2].total() != 1000/clockTicks {
+		t.Errorf("core 2 time = %v, want %v", cores[2].total(), 1000/clockTicks)
+	}
+}
+
+func TestProcCollectorReadCpuTimesMalformed(t *testing.T) {
+	for _, stat := range []string{"cpu 1 2 3\n", "cpux 1 2 3 4 5 6 7 8 9 10\n"} {
+		root := t.TempDir()
+		if err := os.WriteFile(filepath.Join(root, "stat"), []byte(stat), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, _, err := newProcCollector(root).readCpuTimes(); err == nil {
+			t.Errorf("readCpuTimes succeeded on %q, want an error", stat)
+		}
+	}
+}
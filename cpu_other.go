@@ -0,0 +1,46 @@
+//go:build !linux
+
+package main
+
+import (
+	"errors"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+)
+
+// readCpuTimes returns the time spent by the whole CPU and by each core in
+// each state since the system booted.
+func readCpuTimes() (cpuTimes, []cpuTimes, error) {
+	total, err := cpu.Times(false)
+	if err != nil {
+		return cpuTimes{}, nil, err
+	}
+	if len(total) == 0 {
+		return cpuTimes{}, nil, errors.New("no CPU times reported")
+	}
+
+	perCore, err := cpu.Times(true)
+	if err != nil {
+		return cpuTimes{}, nil, err
+	}
+
+	var cores []cpuTimes
+	for _, core := range perCore {
+		cores = append(cores, toCpuTimes(core))
+	}
+
+	return toCpuTimes(total[0]), cores, nil
+}
+
+// toCpuTimes converts the times reported by gopsutil.
+func toCpuTimes(t cpu.TimesStat) cpuTimes {
+	return cpuTimes{
+		User:   t.User,
+		Nice:   t.Nice,
+		System: t.System,
+		Idle:   t.Idle,
+		IOWait: t.Iowait,
+		Irq:    t.Irq + t.Softirq,
+		Steal:  t.Steal,
+	}
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// tcpStates are the names of the states of a TCP socket, indexed by the
+// number the kernel shows in the net/tcp files.
+var tcpStates = map[string]string{
+	"01": "ESTABLISHED",
+	"02": "SYN_SENT",
+	"03": "SYN_RECV",
+	"04": "FIN_WAIT1",
+	"05": "FIN_WAIT2",
+	"06": "TIME_WAIT",
+	"07": "CLOSE",
+	"08": "CLOSE_WAIT",
+	"09": "LAST_ACK",
+	"0A": "LISTEN",
+	"0B": "CLOSING",
+}
+
+// socketInfo is what the net files of procfs tell about a socket.
+type socketInfo struct {
+	// IPv4, IPv6 or unix, as lsof shows them.
+	family string
+	// Addresses and state, or the path of a unix socket.
+	name string
+}
+
+// getOpenFiles returns the files the process with the given ID has open.
+func getOpenFiles(pId int32) ([]openFile, error) {
+	return processesCollector.readOpenFiles(pId)
+}
+
+// readOpenFiles resolves each file descriptor of a process from its fd
+// directory, sorted by their number. The sockets are looked up in the net
+// files of the process, as it may be in its own network namespace. It fails
+// when the descriptors of the process can't be read, as only its owner can.
+func (c *procCollector) readOpenFiles(pId int32) ([]openFile, error) {
+	dir := filepath.Join(c.root, strconv.Itoa(int(pId)))
+
+	entries, err := os.ReadDir(filepath.Join(dir, "fd"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Sockets are only read when the process has any.
+	var sockets map[string]socketInfo
+
+	var files []openFile
+	for _, entry := range entries {
+		fd, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		// Descriptors closed while reading are skipped.
+		path := filepath.Join(dir, "fd", entry.Name())
+		link, err := os.Readlink(path)
+		if err != nil {
+			continue
+		}
+
+		file := openFile{FD: fd, Mode: readFdMode(filepath.Join(dir, "fdinfo", entry.Name())), Name: link}
+		switch {
+		case strings.HasPrefix(link, "socket:["):
+			if sockets == nil {
+				sockets = readSockets(filepath.Join(dir, "net"))
+			}
+
+			file.Node = strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")
+			file.Type = "sock"
+			if socket, ok := sockets[file.Node]; ok {
+				file.Type, file.Name = socket.family, socket.name
+			}
+		case strings.HasPrefix(link, "pipe:["):
+			file.Type = "FIFO"
+			file.Node = strings.TrimSuffix(strings.TrimPrefix(link, "pipe:["), "]")
+			file.Name = "pipe"
+		case strings.HasPrefix(link, "anon_inode:"):
+			file.Type = "a_inode"
+			file.Name = strings.TrimPrefix(link, "anon_inode:")
+		default:
+			file.Type = "unknown"
+			if info, err := os.Stat(path); err == nil {
+				file.Type = fileType(info.Mode())
+				if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+					file.Node = strconv.FormatUint(stat.Ino, 10)
+				}
+			}
+		}
+
+		files = append(files, file)
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].FD < files[j].FD })
+
+	return files, nil
+}
+
+// fileType returns the kind of file as lsof shows it.
+func fileType(mode os.FileMode) string {
+	switch {
+	case mode.IsDir():
+		return "DIR"
+	case mode&os.ModeCharDevice != 0:
+		return "CHR"
+	case mode&os.ModeDevice != 0:
+		return "BLK"
+	case mode&os.ModeNamedPipe != 0:
+		return "FIFO"
+	case mode&os.ModeSocket != 0:
+		return "sock"
+	default:
+		return "REG"
+	}
+}
+
+// readFdMode returns the access mode of a file descriptor from the flags of
+// its fdinfo file: r, w or u for read and write.
+func readFdMode(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// flags:	0100002, in octal.
+		name, value, _ := strings.Cut(scanner.Text(), ":")
+		if name != "flags" {
+			continue
+		}
+
+		flags, err := strconv.ParseUint(strings.TrimSpace(value), 8, 64)
+		if err != nil {
+			return ""
+		}
+
+		switch flags & syscall.O_ACCMODE {
+		case syscall.O_RDONLY:
+			return "r"
+		case syscall.O_WRONLY:
+			return "w"
+		default:
+			return "u"
+		}
+	}
+
+	return ""
+}
+
+// readSockets returns the sockets listed in the tcp, udp and unix files of a
+// net directory of procfs, indexed by their inode.
+func readSockets(netDir string) map[string]socketInfo {
+	sockets := make(map[string]socketInfo)
+
+	for _, table := range []struct {
+		file     string
+		family   string
+		protocol string
+	}{
+		{"tcp", "IPv4", "TCP"},
+		{"tcp6", "IPv6", "TCP"},
+		{"udp", "IPv4", "UDP"},
+		{"udp6", "IPv6", "UDP"},
+	} {
+		readInetSockets(filepath.Join(netDir, table.file), table.family, table.protocol, sockets)
+	}
+	readUnixSockets(filepath.Join(netDir, "unix"), sockets)
+
+	return sockets
+}
+
+// readInetSockets adds the sockets of a tcp or udp file of procfs to sockets,
+// named like lsof does: protocol, local and remote addresses and the state.
+func readInetSockets(path, family, protocol string, sockets map[string]socketInfo) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	lines := strings.Split(string(data), "\n")
+	// The first line is the heading.
+	for _, line := range lines[min(len(lines), 1):] {
+		// sl local_address rem_address st tx_queue:rx_queue tr:tm->when
+		// retrnsmt uid timeout inode ...
+		fields := strings.Fields(line)
+		if len(fields) < 10 {
+			continue
+		}
+
+		local, err := parseSocketAddress(fields[1])
+		if err != nil {
+			continue
+		}
+		remote, err := parseSocketAddress(fields[2])
+		if err != nil {
+			continue
+		}
+
+		name := protocol + " " + local
+		if !strings.HasSuffix(remote, ":0") {
+			name += "->" + remote
+		}
+		if state, ok := tcpStates[fields[3]]; ok && protocol == "TCP" {
+			name += " (" + state + ")"
+		}
+
+		sockets[fields[9]] = socketInfo{family: family, name: name}
+	}
+}
+
+// parseSocketAddress parses an address of a tcp or udp file of procfs: the
+// IP as 32 bits hexadecimal words, printed from memory in the byte order of
+// the host, and the port in hexadecimal.
+func parseSocketAddress(s string) (string, error) {
+	hexIP, hexPort, ok := strings.Cut(s, ":")
+	if !ok || len(hexIP)%8 != 0 {
+		return "", fmt.Errorf("malformed socket address %q", s)
+	}
+
+	ip := make([]byte, len(hexIP)/2)
+	for i := 0; i < len(hexIP); i += 8 {
+		word, err := strconv.ParseUint(hexIP[i:i+8], 16, 32)
+		if err != nil {
+			return "", fmt.Errorf("malformed socket address %q", s)
+		}
+		binary.NativeEndian.PutUint32(ip[i/2:], uint32(word))
+	}
+
+	port, err := strconv.ParseUint(hexPort, 16, 16)
+	if err != nil {
+		return "", err
+	}
+
+	return net.JoinHostPort(net.IP(ip).String(), strconv.FormatUint(port, 10)), nil
+}
+
+// readUnixSockets adds the sockets of a unix file of procfs to sockets, named
+// by their path and type. Unnamed ones only show their type.
+func readUnixSockets(path string, sockets map[string]socketInfo) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	types := map[string]string{"0001": "STREAM", "0002": "DGRAM", "0005": "SEQPACKET"}
+
+	lines := strings.Split(string(data), "\n")
+	// The first line is the heading.
+	for _, line := range lines[min(len(lines), 1):] {
+		// Num RefCount Protocol Flags Type St Inode [Path]
+		fields := strings.Fields(line)
+		if len(fields) < 7 {
+			continue
+		}
+
+		name := "type=" + types[fields[4]]
+		if len(fields) > 7 {
+			name = fields[7] + " " + name
+		}
+
+		sockets[fields[6]] = socketInfo{family: "unix", name: name}
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// procNetAddress formats an IP and port as the tcp and udp files of procfs
+// do, each 32 bits word of the IP printed in the byte order of the host. On
+// little endian hosts 127.0.0.1:8080 is 0100007F:1F90.
+func procNetAddress(ip string, port int) string {
+	addr := net.ParseIP(ip)
+	if v4 := addr.To4(); v4 != nil {
+		addr = v4
+	}
+
+	var s strings.Builder
+	for i := 0; i < len(addr); i += 4 {
+		fmt.Fprintf(&s, "%08X", binary.NativeEndian.Uint32(addr[i:]))
+	}
+
+	return fmt.Sprintf("%s:%04X", s.String(), port)
+}
+
+func TestParseSocketAddress(t *testing.T) {
+	tests := []struct {
+		ip   string
+		port int
+		want string
+	}{
+		{"127.0.0.1", 8080, "127.0.0.1:8080"},
+		{"192.168.1.20", 443, "192.168.1.20:443"},
+		{"0.0.0.0", 0, "0.0.0.0:0"},
+		{"::1", 22, "[::1]:22"},
+		{"2001:db8::8a2e:370:7334", 5353, "[2001:db8::8a2e:370:7334]:5353"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			got, err := parseSocketAddress(procNetAddress(tt.ip, tt.port))
+			if err != nil {
+				t.Fatalf("parseSocketAddress: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("parseSocketAddress = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	for _, s := range []string{"0100007F", "0100007:1F90", "0100007G:1F90", "0100007F:FFFFF"} {
+		if _, err := parseSocketAddress(s); err == nil {
+			t.Errorf("parseSocketAddress(%q) succeeded, want an error", s)
+		}
+	}
+}
+
+func TestReadInetSockets(t *testing.T) {
+	tcp := "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n" +
+		fmt.Sprintf("   0: %s %s 0A 00000000:00000000 00:00000000 00000000     0        0 95715 1 0000000000000000 100 0 0 10 0\n",
+			procNetAddress("0.0.0.0", 8080), procNetAddress("0.0.0.0", 0)) +
+		fmt.Sprintf("   1: %s %s 01 00000000:00000000 02:000A7D4C 00000000  1000        0 93783 1 0000000000000000 20 4 30 10 -1\n",
+			procNetAddress("127.0.0.1", 48271), procNetAddress("127.0.0.1", 51790))
+	path := filepath.Join(t.TempDir(), "tcp")
+	if err := os.WriteFile(path, []byte(tcp), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sockets := make(map[string]socketInfo)
+	readInetSockets(path, "IPv4", "TCP", sockets)
+
+	want := map[string]socketInfo{
+		"95715": {family: "IPv4", name: "TCP 0.0.0.0:8080 (LISTEN)"},
+		"93783": {family: "IPv4", name: "TCP 127.0.0.1:48271->127.0.0.1:51790 (ESTABLISHED)"},
+	}
+	if len(sockets) != len(want) {
+		t.Fatalf("readInetSockets found %d sockets, want %d", len(sockets), len(want))
+	}
+	for inode, socket := range want {
+		if sockets[inode] != socket {
+			t.Errorf("socket %s = %+v, want %+v", inode, sockets[inode], socket)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// File that keeps the past samples of the system's usage for the graphs.
+package main
+
+// ringBuffer holds the latest samples of a value, overwriting the oldest one
+// once it is full.
+type ringBuffer struct {
+	samples []float64
+	// Index of the oldest sample.
+	start int
+	// Amount of samples held.
+	size int
+}
+
+// newRingBuffer returns a buffer holding up to capacity samples.
+func newRingBuffer(capacity int) *ringBuffer {
+	return &ringBuffer{samples: make([]float64, max(capacity, 1))}
+}
+
+// push adds a sample, dropping the oldest one when the buffer is full.
+func (r *ringBuffer) push(v float64) {
+	end := (r.start + r.size) % len(r.samples)
+	r.samples[end] = v
+
+	if r.size < len(r.samples) {
+		r.size++
+	} else {
+		r.start = (r.start + 1) % len(r.samples)
+	}
+}
+
+// values returns the samples held, from the oldest to the newest.
+func (r *ringBuffer) values() []float64 {
+	values := make([]float64, r.size)
+	for i := range values {
+		values[i] = r.samples[(r.start+i)%len(r.samples)]
+	}
+
+	return values
+}
+
+// capacity returns the amount of samples the buffer can hold.
+func (r *ringBuffer) capacity() int {
+	return len(r.samples)
+}
+
+// last returns the newest sample, or 0 when there is none.
+func (r *ringBuffer) last() float64 {
+	if r.size == 0 {
+		return 0
+	}
+
+	return r.samples[(r.start+r.size-1)%len(r.samples)]
+}
+
+// history holds the past usage percentages of the CPU, the memory and the
+// disks, one sample per update.
+type history struct {
+	capacity int
+
+	cpu     *ringBuffer
+	cores   []*ringBuffer
+	vMemory *ringBuffer
+	sMemory *ringBuffer
+	// Usage of each disk, indexed by its mount path.
+	disks map[string]*ringBuffer
+	// Mount paths in the order the disks were first seen.
+	diskOrder []string
+}
+
+// newHistory returns a history holding up to capacity samples of each value.
+func newHistory(capacity int) *history {
+	return &history{
+		capacity: capacity,
+		cpu:      newRingBuffer(capacity),
+		vMemory:  newRingBuffer(capacity),
+		sMemory:  newRingBuffer(capacity),
+		disks:    make(map[string]*ringBuffer),
+	}
+}
+
+// record adds the current usage shown by the model to the history.
+func (h *history) record(m model) {
+	h.cpu.push(m.CpuAll.Total())
+
+	for i, core := range m.CpuInfo {
+		if i >= len(h.cores) {
+			h.cores = append(h.cores, newRingBuffer(h.capacity))
+		}
+		h.cores[i].push(core.Total())
+	}
+
+	h.vMemory.push(m.VMemoryInfo.UsedPercent)
+	h.sMemory.push(m.SMemoryInfo.UsedPercent)
+
+	// The history of a disk no longer mounted is dropped, so it doesn't come
+	// back when another disk is mounted in the same path.
+	mounted := make(map[string]struct{}, len(m.DisksInfo))
+	for _, disk := range m.DisksInfo {
+		mounted[disk.MountPath] = struct{}{}
+	}
+	order := h.diskOrder[:0]
+	for _, mountPath := range h.diskOrder {
+		if _, ok := mounted[mountPath]; ok {
+			order = append(order, mountPath)
+		} else {
+			delete(h.disks, mountPath)
+		}
+	}
+	h.diskOrder = order
+
+	for _, disk := range m.DisksInfo {
+		buffer, ok := h.disks[disk.MountPath]
+		if !ok {
+			buffer = newRingBuffer(h.capacity)
+			h.disks[disk.MountPath] = buffer
+			h.diskOrder = append(h.diskOrder, disk.MountPath)
+		}
+
+		var usedPercent float64
+		if disk.TotalSize > 0 {
+			usedPercent = float64(disk.UsedSize) / float64(disk.TotalSize) * 100
+		}
+		buffer.push(usedPercent)
+	}
+}
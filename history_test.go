@@ -0,0 +1,28 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHistoryDropsUnmountedDisks(t *testing.T) {
+	h := newHistory(10)
+	disk := func(mountPath string, used uint64) diskInfo {
+		return diskInfo{MountPath: mountPath, TotalSize: 100, UsedSize: used}
+	}
+
+	h.record(model{DisksInfo: []diskInfo{disk("/", 10), disk("/mnt", 50)}})
+	h.record(model{DisksInfo: []diskInfo{disk("/", 20)}})
+	if _, ok := h.disks["/mnt"]; ok || !reflect.DeepEqual(h.diskOrder, []string{"/"}) {
+		t.Fatalf("disks after unmounting /mnt = %v, want only /", h.diskOrder)
+	}
+
+	// A disk mounted again in the same path starts a new history.
+	h.record(model{DisksInfo: []diskInfo{disk("/", 30), disk("/mnt", 90)}})
+	if got := h.disks["/mnt"].values(); !reflect.DeepEqual(got, []float64{90}) {
+		t.Errorf("history of /mnt = %v, want [90]", got)
+	}
+	if got := h.disks["/"].values(); !reflect.DeepEqual(got, []float64{10, 20, 30}) {
+		t.Errorf("history of / = %v, want [10 20 30]", got)
+	}
+}
@@ -3,11 +3,67 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// options holds the settings given through the command line.
+type options struct {
+	// Shows the CPU usage of each process relative to a single core, so a
+	// process using two cores shows 200%. Otherwise it is relative to the
+	// whole CPU.
+	irixMode bool
+	// Period of time shown by the graphs.
+	history time.Duration
+	// File systems shown in the disks table.
+	diskFilter diskFilter
+	// Multiples the sizes are shown with.
+	units sizeUnits
+	// Path where the cgroup v2 hierarchy is mounted.
+	cgroupRoot string
+}
+
 func main() {
-	p := tea.NewProgram(NewModel(), tea.WithAltScreen())
+	var opts options
+	flag.BoolVar(&opts.irixMode, "irix", true,
+		"show the CPU usage of each process relative to a single core instead of the whole CPU")
+	flag.DurationVar(&opts.history, "history", 10*time.Minute,
+		"period of time shown by the graphs, one sample is kept per update")
+	si := flag.Bool("si", false,
+		"show sizes in powers of 1000 (kB, MB, GB) instead of powers of 1024 (KiB, MiB, GiB)")
+	flag.StringVar(&opts.cgroupRoot, "cgroup-root", "/sys/fs/cgroup",
+		"path where the cgroup v2 hierarchy shown in the cgroups view is mounted")
+	fsTypes := flag.String("fs-types", strings.Join(defaultFsTypes, ","),
+		"comma separated file system types shown in the disks table, or all")
+	fsExcludeTypes := flag.String("fs-exclude-types", "",
+		"comma separated file system types hidden from the disks table")
+	fsDevice := flag.String("fs-device", "",
+		"regular expression the device of the file systems shown must match")
+	fsExcludeDevice := flag.String("fs-exclude-device", "",
+		"regular expression matching the devices of the file systems hidden")
+	fsMount := flag.String("fs-mount", "",
+		"regular expression the mount path of the file systems shown must match")
+	fsExcludeMount := flag.String("fs-exclude-mount", "",
+		"regular expression matching the mount paths of the file systems hidden")
+	flag.Parse()
+
+	if *si {
+		opts.units = siUnits
+	}
+
+	var err error
+	opts.diskFilter, err = newDiskFilter(*fsTypes, *fsExcludeTypes, *fsDevice, *fsExcludeDevice, *fsMount, *fsExcludeMount)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	p := tea.NewProgram(NewModel(opts), tea.WithAltScreen())
 	if err := p.Start(); err != nil {
 		// Many unaccounted errors can come from sys calls.
 		// They are unlikely to occur.
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// getMemoryMaps returns the memory mappings of the process with the given ID.
+func getMemoryMaps(pId int32) (memoryMaps, error) {
+	return processesCollector.readMemoryMaps(pId)
+}
+
+// readMemoryMaps reads the mappings of a process from its smaps file and
+// their totals from smaps_rollup, which adds up the proportional sizes
+// without rounding each mapping. Kernels older than 4.14 have no rollup, so
+// the mappings are added up instead. It fails when the smaps file can't be
+// read, as only the owner of the process can.
+func (c *procCollector) readMemoryMaps(pId int32) (memoryMaps, error) {
+	dir := filepath.Join(c.root, strconv.Itoa(int(pId)))
+
+	data, err := os.ReadFile(filepath.Join(dir, "smaps"))
+	if err != nil {
+		return memoryMaps{}, err
+	}
+
+	maps := memoryMaps{PId: pId, Mappings: parseSmaps(string(data))}
+	for _, mapping := range maps.Mappings {
+		maps.Total.Count++
+		maps.Total.Size += mapping.Size
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "smaps_rollup")); err == nil {
+		if rollup := parseSmaps(string(data)); len(rollup) == 1 {
+			maps.Total.Rss, maps.Total.Pss = rollup[0].Rss, rollup[0].Pss
+			maps.Total.Swap, maps.Total.Dirty = rollup[0].Swap, rollup[0].Dirty
+
+			return maps, nil
+		}
+	}
+
+	for _, mapping := range maps.Mappings {
+		maps.Total.Rss += mapping.Rss
+		maps.Total.Pss += mapping.Pss
+		maps.Total.Swap += mapping.Swap
+		maps.Total.Dirty += mapping.Dirty
+	}
+
+	return maps, nil
+}
+
+// parseSmaps parses the mappings of a smaps or smaps_rollup file. Each one
+// starts with a line like the ones of the maps file, followed by its sizes.
+func parseSmaps(data string) []memoryMapping {
+	var mappings []memoryMapping
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		// Size:    160 kB
+		if strings.HasSuffix(fields[0], ":") {
+			if len(mappings) == 0 || len(fields) != 3 || fields[2] != "kB" {
+				continue
+			}
+			size, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				continue
+			}
+			size *= 1024
+
+			mapping := &mappings[len(mappings)-1]
+			switch fields[0] {
+			case "Size:":
+				mapping.Size = size
+			case "Rss:":
+				mapping.Rss = size
+			case "Pss:":
+				mapping.Pss = size
+			case "Swap:":
+				mapping.Swap = size
+			case "Shared_Dirty:", "Private_Dirty:":
+				mapping.Dirty += size
+			}
+
+			continue
+		}
+
+		// address perms offset dev inode [pathname]
+		if len(fields) < 5 {
+			continue
+		}
+
+		start, _, _ := strings.Cut(fields[0], "-")
+		mapping := memoryMapping{Address: fields[0], Perms: fields[1], Name: "[anon]", Count: 1}
+		mapping.Start, _ = strconv.ParseUint(start, 16, 64)
+		if len(fields) > 5 {
+			mapping.Name = strings.Join(fields[5:], " ")
+		}
+
+		mappings = append(mappings, mapping)
+	}
+
+	return mappings
+}
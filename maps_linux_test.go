@@ -0,0 +1,114 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProcCollectorReadMemoryMaps(t *testing.T) {
+	c := newProcCollector(fixtureProc)
+
+	maps, err := c.readMemoryMaps(42)
+	if err != nil {
+		t.Fatalf("readMemoryMaps: %v", err)
+	}
+
+	want := []memoryMapping{
+		{Start: 0x55d0c0a00000, Address: "55d0c0a00000-55d0c0a21000", Perms: "r-xp", Name: "/usr/bin/weird",
+			Count: 1, Size: 132 * KB, Rss: 100 * KB, Pss: 100 * KB},
+		{Start: 0x55d0c1e00000, Address: "55d0c1e00000-55d0c1e42000", Perms: "rw-p", Name: "[heap]",
+			Count: 1, Size: 264 * KB, Rss: 200 * KB, Pss: 200 * KB, Swap: 8 * KB, Dirty: 200 * KB},
+		{Start: 0x7f2a1c000000, Address: "7f2a1c000000-7f2a1c021000", Perms: "rw-p", Name: "[anon]",
+			Count: 1, Size: 132 * KB, Rss: 64 * KB, Pss: 64 * KB, Dirty: 64 * KB},
+		{Start: 0x7f2a1c200000, Address: "7f2a1c200000-7f2a1c3b5000", Perms: "r-xp", Name: "/usr/lib/libc.so.6",
+			Count: 1, Size: 1748 * KB, Rss: 900 * KB, Pss: 150 * KB},
+		// Both the shared and the private dirty pages are counted.
+		{Start: 0x7f2a1c3b5000, Address: "7f2a1c3b5000-7f2a1c3b9000", Perms: "rw-p", Name: "/usr/lib/libc.so.6",
+			Count: 1, Size: 16 * KB, Rss: 16 * KB, Pss: 16 * KB, Dirty: 16 * KB},
+		{Start: 0x7ffd5e000000, Address: "7ffd5e000000-7ffd5e021000", Perms: "rw-p", Name: "[stack]",
+			Count: 1, Size: 132 * KB, Rss: 12 * KB, Pss: 12 * KB, Swap: 4 * KB, Dirty: 12 * KB},
+	}
+	if !reflect.DeepEqual(maps.Mappings, want) {
+		t.Errorf("mappings =\n%+v\nwant\n%+v", maps.Mappings, want)
+	}
+
+	// The proportional size comes from smaps_rollup, which isn't rounded
+	// for each mapping.
+	wantTotal := memoryMapping{Count: 6, Size: 2424 * KB, Rss: 1292 * KB, Pss: 541 * KB, Swap: 12 * KB, Dirty: 292 * KB}
+	if maps.Total != wantTotal {
+		t.Errorf("total = %+v, want %+v", maps.Total, wantTotal)
+	}
+}
+
+func TestProcCollectorReadMemoryMapsWithoutRollup(t *testing.T) {
+	maps, err := newProcCollector(fixtureProc).readMemoryMaps(1)
+	if err != nil {
+		t.Fatalf("readMemoryMaps: %v", err)
+	}
+
+	// Without smaps_rollup, as on kernels older than 4.14, the mappings are
+	// added up.
+	want := memoryMapping{Count: 1, Size: 132 * KB, Rss: 100 * KB, Pss: 50 * KB}
+	if maps.Total != want {
+		t.Errorf("total = %+v, want %+v", maps.Total, want)
+	}
+
+	if _, err := newProcCollector(fixtureProc).readMemoryMaps(2); err == nil {
+		t.Error("readMemoryMaps succeeded without a smaps file, want an error")
+	}
+}
+
+func TestGroupMappings(t *testing.T) {
+	maps, err := newProcCollector(fixtureProc).readMemoryMaps(42)
+	if err != nil {
+		t.Fatalf("readMemoryMaps: %v", err)
+	}
+
+	groups := groupMappings(maps.Mappings)
+	sortMappings(groups, "Rss", true)
+
+	want := []memoryMapping{
+		{Start: 0x7f2a1c200000, Name: "/usr/lib/libc.so.6", Count: 2, Size: 1764 * KB, Rss: 916 * KB,
+			Pss: 166 * KB, Dirty: 16 * KB},
+		{Start: 0x55d0c1e00000, Name: "[heap]", Count: 1, Size: 264 * KB, Rss: 200 * KB, Pss: 200 * KB,
+			Swap: 8 * KB, Dirty: 200 * KB},
+		{Start: 0x55d0c0a00000, Name: "/usr/bin/weird", Count: 1, Size: 132 * KB, Rss: 100 * KB, Pss: 100 * KB},
+		{Start: 0x7f2a1c000000, Name: "[anon]", Count: 1, Size: 132 * KB, Rss: 64 * KB, Pss: 64 * KB,
+			Dirty: 64 * KB},
+		{Start: 0x7ffd5e000000, Name: "[stack]", Count: 1, Size: 132 * KB, Rss: 12 * KB, Pss: 12 * KB,
+			Swap: 4 * KB, Dirty: 12 * KB},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("groupMappings =\n%+v\nwant\n%+v", groups, want)
+	}
+}
+
+func TestSortMappings(t *testing.T) {
+	mappings := []memoryMapping{
+		{Start: 3, Name: "b", Rss: 10},
+		{Start: 1, Name: "a", Rss: 10},
+		{Start: 2, Name: "c", Rss: 30},
+	}
+
+	starts := func() []uint64 {
+		var s []uint64
+		for _, m := range mappings {
+			s = append(s, m.Start)
+		}
+		return s
+	}
+
+	// Ties are broken by the address, whatever the order.
+	sortMappings(mappings, "Rss", true)
+	if got, want := starts(), []uint64{2, 1, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sorted by descending RSS = %v, want %v", got, want)
+	}
+	sortMappings(mappings, "Name", false)
+	if got, want := starts(), []uint64{1, 3, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sorted by name = %v, want %v", got, want)
+	}
+	sortMappings(mappings, "unknown", false)
+	if got, want := starts(), []uint64{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sorted by an unknown key = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// blockSysRoot is the sysfs directory holding a subdirectory for each block
+// device.
+var blockSysRoot = "/sys/block"
+
+// memoryDetailNames are the lines of /proc/meminfo shown in the memory details
+// view, in order. The ones missing from the running kernel are left out.
+var memoryDetailNames = []string{
+	"MemTotal", "MemFree", "MemAvailable", "Buffers", "Cached", "SwapCached",
+	"Active", "Inactive", "Shmem", "Dirty", "Writeback", "AnonPages", "Mapped",
+	"Slab", "SReclaimable", "SUnreclaim", "KernelStack", "PageTables",
+	"CommitLimit", "Committed_AS", "HugePages_Total", "HugePages_Free",
+	"HugePages_Rsvd", "HugePages_Surp", "Hugepagesize", "Zswap", "Zswapped",
+}
+
+// getMemoryDetails returns the memory accounting of the kernel and the usage
+// of the zram devices. Values that can't be read are left out.
+func getMemoryDetails() memoryDetails {
+	return processesCollector.readMemoryDetails(blockSysRoot)
+}
+
+// readMemoryDetails reads the memory accounting from the meminfo file in the
+// procfs root and the zram devices from the block devices under blockRoot.
+func (c *procCollector) readMemoryDetails(blockRoot string) memoryDetails {
+	var details memoryDetails
+
+	// Ignoring errors as the view notices the values are missing.
+	meminfo, _ := c.readMemInfo()
+	for _, name := range memoryDetailNames {
+		if detail, ok := meminfo[name]; ok {
+			details.Entries = append(details.Entries, detail)
+		}
+	}
+
+	details.Zram = readZram(blockRoot)
+
+	return details
+}
+
+// readMemInfo returns the lines of the meminfo file in the procfs root by
+// their name. Values in kB are converted to bytes.
+func (c *procCollector) readMemInfo() (map[string]memoryDetail, error) {
+	f, err := os.Open(filepath.Join(c.root, "meminfo"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	meminfo := make(map[string]memoryDetail)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Name:     value [kB]
+		name, rest, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+
+		value, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		detail := memoryDetail{Name: name, Value: value}
+		if len(fields) > 1 && fields[1] == "kB" {
+			detail.Value *= KB
+			detail.IsSize = true
+		}
+		meminfo[name] = detail
+	}
+
+	return meminfo, scanner.Err()
+}
+
+// readZram returns the usage of each zram device under root.
+func readZram(root string) []zramInfo {
+	paths, _ := filepath.Glob(filepath.Join(root, "zram[0-9]*"))
+
+	var devices []zramInfo
+	for _, path := range paths {
+		data, err := os.ReadFile(filepath.Join(path, "mm_stat"))
+		if err != nil {
+			continue
+		}
+
+		// orig_data_size compr_data_size mem_used_total mem_limit ...
+		fields := strings.Fields(string(data))
+		if len(fields) < 3 {
+			continue
+		}
+
+		var stat [3]uint64
+		for i := range stat {
+			stat[i], _ = strconv.ParseUint(fields[i], 10, 64)
+		}
+
+		var diskSize uint64
+		if data, err := os.ReadFile(filepath.Join(path, "disksize")); err == nil {
+			diskSize, _ = strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+		}
+
+		devices = append(devices, zramInfo{
+			Device:         filepath.Base(path),
+			DiskSize:       diskSize,
+			OriginalSize:   stat[0],
+			CompressedSize: stat[1],
+			MemoryUsed:     stat[2],
+		})
+	}
+
+	return devices
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// fixtureBlockSys is a sysfs directory of the block devices with a zram
+// device in use and another one not set up yet, without a mm_stat file.
+const fixtureBlockSys = "testdata/sys/block"
+
+func TestProcCollectorReadMemoryDetails(t *testing.T) {
+	details := newProcCollector(fixtureProc).readMemoryDetails(fixtureBlockSys)
+
+	// In the order of memoryDetailNames, only the lines in the file.
+	want := []memoryDetail{
+		{Name: "MemTotal", Value: 8000000 * KB, IsSize: true},
+		{Name: "MemFree", Value: 4000000 * KB, IsSize: true},
+		{Name: "MemAvailable", Value: 6000000 * KB, IsSize: true},
+		{Name: "Buffers", Value: 200000 * KB, IsSize: true},
+		{Name: "Cached", Value: 1500000 * KB, IsSize: true},
+		// A count rather than a size.
+		{Name: "HugePages_Total", Value: 4},
+		{Name: "Hugepagesize", Value: 2048 * KB, IsSize: true},
+		{Name: "Zswap", Value: 50000 * KB, IsSize: true},
+		{Name: "Zswapped", Value: 150000 * KB, IsSize: true},
+	}
+	if !reflect.DeepEqual(details.Entries, want) {
+		t.Errorf("entries =\n%+v\nwant\n%+v", details.Entries, want)
+	}
+
+	wantZram := []zramInfo{{Device: "zram0", DiskSize: 8589934592, OriginalSize: 4194304000,
+		CompressedSize: 1048576000, MemoryUsed: 1073741824}}
+	if !reflect.DeepEqual(details.Zram, wantZram) {
+		t.Errorf("zram = %+v, want %+v", details.Zram, wantZram)
+	}
+}
+
+func TestProcCollectorReadMemoryDetailsWithoutZswapAndZram(t *testing.T) {
+	root := t.TempDir()
+	meminfo := "MemTotal:        8000000 kB\nMemFree:         4000000 kB\n"
+	if err := os.WriteFile(filepath.Join(root, "meminfo"), []byte(meminfo), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	details := newProcCollector(root).readMemoryDetails(filepath.Join(root, "block"))
+	for _, entry := range details.Entries {
+		if entry.Name == "Zswap" || entry.Name == "Zswapped" {
+			t.Errorf("entry %s shown on a kernel without zswap", entry.Name)
+		}
+	}
+	if len(details.Entries) != 2 {
+		t.Errorf("got %d entries, want 2", len(details.Entries))
+	}
+	if details.Zram != nil {
+		t.Errorf("zram = %+v without zram devices, want nothing", details.Zram)
+	}
+}
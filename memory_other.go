@@ -0,0 +1,9 @@
+//go:build !linux
+
+package main
+
+// getMemoryDetails returns the memory accounting of the kernel. It is only
+// read from procfs, so nothing is returned on other systems.
+func getMemoryDetails() memoryDetails {
+	return memoryDetails{}
+}
@@ -0,0 +1,472 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// processesLess compares two processes by the value of a processes table
+// column, indexed by the column's key.
+var processesLess = map[string]func(a, b processInfo) bool{
+	"PId":           func(a, b processInfo) bool { return a.PId < b.PId },
+	"PPId":          func(a, b processInfo) bool { return a.PPId < b.PPId },
+	"Priority":      func(a, b processInfo) bool { return a.Priority < b.Priority },
+	"User":          func(a, b processInfo) bool { return a.User < b.User },
+	"CpuPercentage": func(a, b processInfo) bool { return a.CpuPercentage < b.CpuPercentage },
+	"MemPercentage": func(a, b processInfo) bool { return a.MemPercentage < b.MemPercentage },
+	"Resident":      func(a, b processInfo) bool { return a.Resident < b.Resident },
+	"Virtual":       func(a, b processInfo) bool { return a.Virtual < b.Virtual },
+	"Shared":        func(a, b processInfo) bool { return a.Shared < b.Shared },
+	"Name":          func(a, b processInfo) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) },
+	"ExeP":          func(a, b processInfo) bool { return a.ExeP < b.ExeP },
+	"Cmdline":       func(a, b processInfo) bool { return a.Cmdline < b.Cmdline },
+	"State":         func(a, b processInfo) bool { return a.State < b.State },
+	"Threads":       func(a, b processInfo) bool { return a.Threads < b.Threads },
+	"Nice":          func(a, b processInfo) bool { return a.Nice < b.Nice },
+	"StartTime":     func(a, b processInfo) bool { return a.StartTime.Before(b.StartTime) },
+	"CpuTime":       func(a, b processInfo) bool { return a.CpuTime < b.CpuTime },
+	"Container":     func(a, b processInfo) bool { return a.Container < b.Container },
+	// Processes whose I/O is unknown go below the ones that did none.
+	"IOReadRate":  func(a, b processInfo) bool { return ioRate(a, a.IOReadRate) < ioRate(b, b.IOReadRate) },
+	"IOWriteRate": func(a, b processInfo) bool { return ioRate(a, a.IOWriteRate) < ioRate(b, b.IOWriteRate) },
+}
+
+// ioRate returns the given I/O rate of the process, or -1 when its I/O is
+// unknown.
+func ioRate(p processInfo, rate float64) float64 {
+	if !p.IOKnown {
+		return -1
+	}
+
+	return rate
+}
+
+// sortProcesses sorts the processes in place by the given column key. The
+// table can't sort them by itself as some columns are rendered as formatted
+// strings, like the memory ones. Ties are broken by the process ID so rows
+// don't jump around between updates.
+func sortProcesses(processes []processInfo, key string, desc bool) {
+	less, ok := processesLess[key]
+	if !ok {
+		less = processesLess["PId"]
+	}
+
+	sort.SliceStable(processes, func(i, j int) bool {
+		a, b := processes[i], processes[j]
+		if less(a, b) {
+			return !desc
+		}
+		if less(b, a) {
+			return desc
+		}
+
+		return a.PId < b.PId
+	})
+}
+
+// processTreeRow is a process placed in the processes tree.
+type processTreeRow struct {
+	process processInfo
+	// Branches drawn before the name of the process, showing its depth.
+	prefix string
+	// Whether the subtree of the process is folded. Its usage then includes
+	// the one of all its descendants.
+	collapsed bool
+}
+
+// processTree orders the processes as a tree built from their parent
+// process IDs, with the siblings sorted by the given column key. Processes
+// whose parent isn't listed are roots. The descendants of the collapsed
+// processes are left out.
+func processTree(processes []processInfo, key string, desc bool, collapsed map[int32]struct{}) []processTreeRow {
+	listed := make(map[int32]struct{}, len(processes))
+	for _, process := range processes {
+		listed[process.PId] = struct{}{}
+	}
+
+	var roots []processInfo
+	children := make(map[int32][]processInfo)
+	for _, process := range processes {
+		if _, ok := listed[process.PPId]; ok && process.PPId != process.PId {
+			children[process.PPId] = append(children[process.PPId], process)
+		} else {
+			roots = append(roots, process)
+		}
+	}
+
+	var rows []processTreeRow
+	var walk func(process processInfo, branch, indent string)
+	walk = func(process processInfo, branch, indent string) {
+		row := processTreeRow{process: process, prefix: branch}
+
+		kids := children[process.PId]
+		if _, ok := collapsed[process.PId]; ok && len(kids) > 0 {
+			row.collapsed = true
+			addSubtreeUsage(&row.process, children)
+			rows = append(rows, row)
+
+			return
+		}
+		rows = append(rows, row)
+
+		sortProcesses(kids, key, desc)
+		for i, kid := range kids {
+			if i == len(kids)-1 {
+				walk(kid, indent+"└─ ", indent+"   ")
+			} else {
+				walk(kid, indent+"├─ ", indent+"│  ")
+			}
+		}
+	}
+
+	sortProcesses(roots, key, desc)
+	for _, root := range roots {
+		walk(root, "", "")
+	}
+
+	return rows
+}
+
+// addSubtreeUsage adds the CPU, memory and I/O usage of every descendant of the
+// process to its own.
+func addSubtreeUsage(process *processInfo, children map[int32][]processInfo) {
+	var add func(pId int32)
+	add = func(pId int32) {
+		for _, kid := range children[pId] {
+			addUsage(process, kid)
+			add(kid.PId)
+		}
+	}
+
+	add(process.PId)
+}
+
+// memPercentage returns the percentage of the physical memory a resident
+// size takes, or zero when the total memory is unknown.
+func memPercentage(resident, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return float64(resident) / float64(total) * 100
+}
+
+// addUsage adds the CPU, memory and I/O usage of a process to the one of
+// another.
+func addUsage(to *processInfo, process processInfo) {
+	to.CpuPercentage += process.CpuPercentage
+	to.MemPercentage += process.MemPercentage
+	to.Resident += process.Resident
+	to.Virtual += process.Virtual
+	to.Shared += process.Shared
+	to.IOReadRate += process.IOReadRate
+	to.IOWriteRate += process.IOWriteRate
+}
+
+// processGroup is the processes belonging to the same container, pod or
+// systemd unit.
+type processGroup struct {
+	// Sum of the usage of the processes. Only the fields shared by all of
+	// them are set, besides the usage.
+	process processInfo
+	count   int
+}
+
+// groupProcesses groups the processes by their container, see cgroupLabel,
+// adding up their usage. The groups are sorted by the given column key, ties
+// broken by the container as they all lack a process ID.
+func groupProcesses(processes []processInfo, key string, desc bool) []processGroup {
+	var groups []processGroup
+	index := make(map[string]int)
+	for _, process := range processes {
+		i, ok := index[process.Container]
+		if !ok {
+			i = len(groups)
+			index[process.Container] = i
+			groups = append(groups, processGroup{process: processInfo{
+				Container: process.Container,
+				Cgroup:    process.Cgroup,
+				User:      process.User,
+				StartTime: process.StartTime,
+			}})
+		}
+
+		group := &groups[i]
+		group.count++
+		addUsage(&group.process, process)
+		group.process.Threads += process.Threads
+		group.process.CpuTime += process.CpuTime
+		group.process.IOKnown = group.process.IOKnown || process.IOKnown
+		if process.StartTime.Before(group.process.StartTime) {
+			group.process.StartTime = process.StartTime
+		}
+		if process.User != group.process.User {
+			group.process.User = ""
+		}
+		// The processes of a pod are in the cgroups of its containers.
+		group.process.Cgroup = commonPath(group.process.Cgroup, process.Cgroup)
+	}
+
+	less, ok := processesLess[key]
+	if !ok {
+		less = processesLess["PId"]
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i].process, groups[j].process
+		if less(a, b) {
+			return !desc
+		}
+		if less(b, a) {
+			return desc
+		}
+
+		return a.Container < b.Container
+	})
+
+	return groups
+}
+
+// commonPath returns the longest path both given paths are under.
+func commonPath(a, b string) string {
+	aDirs, bDirs := strings.Split(a, "/"), strings.Split(b, "/")
+
+	var common []string
+	for i := 0; i < len(aDirs) && i < len(bDirs) && aDirs[i] == bDirs[i]; i++ {
+		common = append(common, aDirs[i])
+	}
+
+	if path := strings.Join(common, "/"); path != "" || !strings.HasPrefix(a, "/") {
+		return path
+	}
+
+	return "/"
+}
+
+// processNumberFields are the numeric values of a process a filter term can
+// compare, like cpu>10, indexed by the name used in the term.
+var processNumberFields = map[string]func(p processInfo) float64{
+	"pid":     func(p processInfo) float64 { return float64(p.PId) },
+	"ppid":    func(p processInfo) float64 { return float64(p.PPId) },
+	"cpu":     func(p processInfo) float64 { return p.CpuPercentage },
+	"mem":     func(p processInfo) float64 { return p.MemPercentage },
+	"prio":    func(p processInfo) float64 { return float64(p.Priority) },
+	"nice":    func(p processInfo) float64 { return float64(p.Nice) },
+	"threads": func(p processInfo) float64 { return float64(p.Threads) },
+}
+
+// processTextFields are the text values of a process a filter term can
+// search in, like user:postgres, indexed by the name used in the term.
+var processTextFields = map[string]func(p processInfo) string{
+	"user":      func(p processInfo) string { return p.User },
+	"name":      func(p processInfo) string { return p.Name },
+	"cmd":       func(p processInfo) string { return p.Cmdline },
+	"exe":       func(p processInfo) string { return p.ExeP },
+	"state":     func(p processInfo) string { return p.State },
+	"container": func(p processInfo) string { return p.Container },
+	"cgroup":    func(p processInfo) string { return p.Cgroup },
+}
+
+// Comparison operators of the filter terms. The two characters ones go first
+// so they aren't taken for their first character.
+var filterOperators = []string{">=", "<=", "!=", ">", "<", "="}
+
+// processFilter matches the processes against the terms of a query written
+// by the user. A process matches when it matches every term.
+type processFilter struct {
+	query string
+	terms []func(p processInfo) bool
+}
+
+// parseProcessFilter parses a query made of terms separated by spaces. A term
+// can be:
+// * field:text, matching the processes whose field contains the text.
+// * field>number, comparing a numeric field with >, <, >=, <=, = or !=.
+// * re:expression, matching the name or command against a regular expression.
+// * Any other text, matching the processes whose name or command contain it.
+// Text is matched ignoring the case.
+func parseProcessFilter(query string) (processFilter, error) {
+	filter := processFilter{query: strings.TrimSpace(query)}
+
+	for _, term := range strings.Fields(query) {
+		match, err := parseFilterTerm(term)
+		if err != nil {
+			return processFilter{}, err
+		}
+		filter.terms = append(filter.terms, match)
+	}
+
+	return filter, nil
+}
+
+// parseFilterTerm parses a single term of a filter query, see
+// parseProcessFilter.
+func parseFilterTerm(term string) (func(p processInfo) bool, error) {
+	for _, op := range filterOperators {
+		i := strings.Index(term, op)
+		if i <= 0 {
+			continue
+		}
+
+		field, ok := processNumberFields[strings.ToLower(term[:i])]
+		if !ok {
+			break
+		}
+
+		value, err := strconv.ParseFloat(term[i+len(op):], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number in %q", term[i+len(op):], term)
+		}
+
+		return func(p processInfo) bool { return compare(field(p), op, value) }, nil
+	}
+
+	if name, text, ok := strings.Cut(term, ":"); ok {
+		name = strings.ToLower(name)
+
+		if name == "re" {
+			re, err := regexp.Compile("(?i)" + text)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regular expression %q: %v", text, err)
+			}
+
+			return func(p processInfo) bool { return re.MatchString(p.Name) || re.MatchString(p.Cmdline) }, nil
+		}
+
+		if field, ok := processTextFields[name]; ok {
+			text = strings.ToLower(text)
+			return func(p processInfo) bool { return strings.Contains(strings.ToLower(field(p)), text) }, nil
+		}
+
+		if field, ok := processNumberFields[name]; ok {
+			value, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a number in %q", text, term)
+			}
+
+			return func(p processInfo) bool { return field(p) == value }, nil
+		}
+	}
+
+	// Terms with an unknown field, like URLs, are searched as they are.
+	text := strings.ToLower(term)
+	return func(p processInfo) bool {
+		return strings.Contains(strings.ToLower(p.Name), text) || strings.Contains(strings.ToLower(p.Cmdline), text)
+	}, nil
+}
+
+// compare applies one of the filterOperators to a and b.
+func compare(a float64, op string, b float64) bool {
+	switch op {
+	case ">=":
+		return a >= b
+	case "<=":
+		return a <= b
+	case "!=":
+		return a != b
+	case ">":
+		return a > b
+	case "<":
+		return a < b
+	default:
+		return a == b
+	}
+}
+
+// active reports whether the filter has any term.
+func (f processFilter) active() bool {
+	return len(f.terms) > 0
+}
+
+// match reports whether the process matches every term of the filter.
+func (f processFilter) match(p processInfo) bool {
+	for _, term := range f.terms {
+		if !term(p) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// taskCounts holds the amount of processes in each state, as shown in the
+// header.
+type taskCounts struct {
+	Total    int
+	Threads  int
+	Running  int
+	Sleeping int
+	Zombie   int
+	Stopped  int
+}
+
+// countTasks counts the processes by their state. Both interruptible and
+// uninterruptible sleep, as well as idle processes, count as sleeping.
+func countTasks(processes []processInfo) taskCounts {
+	counts := taskCounts{Total: len(processes)}
+	for _, process := range processes {
+		counts.Threads += int(process.Threads)
+
+		switch process.State {
+		case "R":
+			counts.Running++
+		case "S", "D", "I", "U":
+			counts.Sleeping++
+		case "Z":
+			counts.Zombie++
+		case "T", "t":
+			counts.Stopped++
+		}
+	}
+
+	return counts
+}
+
+// capabilityNames are the names of the Linux capabilities, indexed by their
+// number, as defined in linux/capability.h.
+var capabilityNames = []string{
+	"chown", "dac_override", "dac_read_search", "fowner", "fsetid", "kill",
+	"setgid", "setuid", "setpcap", "linux_immutable", "net_bind_service",
+	"net_broadcast", "net_admin", "net_raw", "ipc_lock", "ipc_owner",
+	"sys_module", "sys_rawio", "sys_chroot", "sys_ptrace", "sys_pacct",
+	"sys_admin", "sys_boot", "sys_nice", "sys_resource", "sys_time",
+	"sys_tty_config", "mknod", "lease", "audit_write", "audit_control",
+	"setfcap", "mac_override", "mac_admin", "syslog", "wake_alarm",
+	"block_suspend", "audit_read", "perfmon", "bpf", "checkpoint_restore",
+}
+
+// capabilities returns the names of the capabilities in a hexadecimal mask,
+// like the CapEff line of a status file shows it. A mask holding every known
+// capability is shown as all of them.
+func capabilities(mask string) string {
+	bits, err := strconv.ParseUint(mask, 16, 64)
+	if err != nil {
+		return mask
+	}
+
+	all := uint64(1)<<len(capabilityNames) - 1
+	switch {
+	case bits == 0:
+		return "none"
+	case bits&all == all:
+		return "all"
+	}
+
+	var names []string
+	for i := 0; i < 64; i++ {
+		if bits&(1<<i) == 0 {
+			continue
+		}
+
+		if i < len(capabilityNames) {
+			names = append(names, "cap_"+capabilityNames[i])
+		} else {
+			names = append(names, fmt.Sprintf("cap_%d", i))
+		}
+	}
+
+	return strings.Join(names, ", ")
+}
@@ -1,10 +1,15 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os/exec"
+	"runtime"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/mem"
 )
 
 const (
@@ -16,10 +21,21 @@ const (
 	hugeW  = largeW + largeW
 )
 
+// Scheduling classes of the I/O priority of a process. They only exist on
+// Linux and are kept for building the I/O priority menu.
+const (
+	ioPriorityClassNone = iota
+	ioPriorityClassRealtime
+	ioPriorityClassBestEffort
+	ioPriorityClassIdle
+)
+
 // getProcessesInfo, due to the version difference of the ps command between
 // Darwin and Linux based systems, uses fixed columns widths to display the
 // results. It also uses different arguments described in the function body.
-func getProcessesInfo() []processInfo {
+// When irix is false the CPU percentage of each process is divided by the
+// number of cores, so 100% means the whole CPU is being used.
+func getProcessesInfo(irix bool) ([]processInfo, error) {
 	var processes []processInfo
 
 	cmd := "ps"
@@ -33,16 +49,30 @@ func getProcessesInfo() []processInfo {
 	// command : Name of the process. (Kept to maintain uniformity with other operating systems.)
 	// pcpu    : Percentage of the CPU used by the process.
 	// prio    : Priority assigned to the process.
+	// rss     : Resident memory of the process in kilobytes.
+	// vsz     : Virtual memory of the process in kilobytes.
+	// state   : State of the process. Only its first letter is kept.
+	// nice    : Nice value of the process.
+	// time    : CPU time used by the process, in the format minutes:seconds.hundredths.
+	// lstart  : Time the process started, in the format of time.ANSIC.
+	// ppid    : Parent process ID.
 	// args    : The full command of the process with all it's arguments.
 
 	// Each number passed describes the total length of the column in the
 	// command's result. Length is then used for slicing the desired values.
-	keywords := fmt.Sprintf("pid=%s,user=%s,comm=%s,pcpu,pri,command=%s,args", smallW, largeW, hugeW, hugeW)
+	keywords := fmt.Sprintf("pid=%s,user=%s,comm=%s,pcpu,pri,rss=%s,vsz=%s,state=%s,nice=%s,time=%s,lstart=%s,ppid=%s,command=%s,args",
+		smallW, largeW, hugeW, smallW, smallW, smallW, smallW, smallW, largeW, smallW, hugeW)
 	args := []string{"-axcro", keywords}
 
 	output, err := exec.Command(cmd, args...).Output()
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+
+	// Without the total memory the memory percentages are left empty.
+	var memTotal uint64
+	if vm, err := mem.VirtualMemory(); err == nil {
+		memTotal = vm.Total
 	}
 
 	// The result is a process's info in the given format.
@@ -50,31 +80,115 @@ func getProcessesInfo() []processInfo {
 	// Removes the last newline and splits the entire string by the remaining.
 	procStrings := strings.Split(strings.TrimSuffix(string(output), "\n"), "\n")[1:]
 	for _, line := range procStrings {
-		pId, err := strconv.ParseInt(strings.TrimSpace(line[:10]), 10, 32)
+		process, err := parsePsLine(line, irix, memTotal)
 		if err != nil {
-			panic(err)
-		}
-		cpuP, err := strconv.ParseFloat(strings.TrimSpace(line[163:168]), 32)
-		if err != nil {
-			panic(err)
-		}
-		prio, err := strconv.ParseInt(strings.TrimSpace(line[169:172]), 10, 32)
-		if err != nil {
-			panic(err)
-		}
-
-		process := processInfo{
-			PId:           int32(pId),
-			User:          strings.TrimSpace(line[11:61]),
-			Name:          strings.TrimSpace(line[62:162]),
-			Priority:      int32(prio),
-			CpuPercentage: cpuP,
-			Cmdline:       strings.TrimSpace(line[274:]),
-			ExeP:          strings.TrimSpace(line[173:273]),
+			return nil, err
 		}
 
 		processes = append(processes, process)
 	}
 
-	return processes
+	return processes, nil
+}
+
+// psLineMinLength is the length of a line of ps up to the end of its last
+// padded column. Only the arguments can be cut shorter, when empty.
+const psLineMinLength = 390
+
+// parsePsLine builds the information of a process from its line in the
+// output of ps, sliced at the widths given to each column.
+func parsePsLine(line string, irix bool, memTotal uint64) (processInfo, error) {
+	if len(line) < psLineMinLength {
+		return processInfo{}, fmt.Errorf("truncated ps line %q", line)
+	}
+	column := func(from, to int) string {
+		return strings.TrimSpace(line[from:to])
+	}
+
+	pId, err := strconv.ParseInt(column(0, 10), 10, 32)
+	if err != nil {
+		return processInfo{}, fmt.Errorf("parsing the PID of ps line %q: %w", line, err)
+	}
+	cpuP, err := strconv.ParseFloat(column(163, 168), 32)
+	if err != nil {
+		return processInfo{}, fmt.Errorf("parsing the CPU usage of process %d: %w", pId, err)
+	}
+	if !irix {
+		cpuP /= float64(runtime.NumCPU())
+	}
+	prio, err := strconv.ParseInt(column(169, 172), 10, 32)
+	if err != nil {
+		return processInfo{}, fmt.Errorf("parsing the priority of process %d: %w", pId, err)
+	}
+
+	rss, err := strconv.ParseUint(column(173, 183), 10, 64)
+	if err != nil {
+		return processInfo{}, fmt.Errorf("parsing the resident memory of process %d: %w", pId, err)
+	}
+	vsz, err := strconv.ParseUint(column(184, 194), 10, 64)
+	if err != nil {
+		return processInfo{}, fmt.Errorf("parsing the virtual memory of process %d: %w", pId, err)
+	}
+
+	state := column(195, 205)
+	if state == "" {
+		return processInfo{}, fmt.Errorf("no state for process %d", pId)
+	}
+	nice, err := strconv.ParseInt(column(206, 216), 10, 32)
+	if err != nil {
+		return processInfo{}, fmt.Errorf("parsing the nice value of process %d: %w", pId, err)
+	}
+	var minutes, seconds, hundredths int
+	if _, err := fmt.Sscanf(column(217, 227), "%d:%d.%d", &minutes, &seconds, &hundredths); err != nil {
+		return processInfo{}, fmt.Errorf("parsing the CPU time of process %d: %w", pId, err)
+	}
+	startTime, err := time.ParseInLocation(time.ANSIC, column(228, 278), time.Local)
+	if err != nil {
+		return processInfo{}, fmt.Errorf("parsing the start time of process %d: %w", pId, err)
+	}
+	pPId, err := strconv.ParseInt(column(279, 289), 10, 32)
+	if err != nil {
+		return processInfo{}, fmt.Errorf("parsing the parent PID of process %d: %w", pId, err)
+	}
+
+	return processInfo{
+		PId:           int32(pId),
+		PPId:          int32(pPId),
+		User:          column(11, 61),
+		Name:          column(62, 162),
+		Priority:      int32(prio),
+		CpuPercentage: cpuP,
+		Cmdline:       strings.TrimSpace(line[min(psLineMinLength+1, len(line)):]),
+		ExeP:          column(290, psLineMinLength),
+		Resident:      rss * KB,
+		Virtual:       vsz * KB,
+		MemPercentage: memPercentage(rss*KB, memTotal),
+		State:         state[:1],
+		Nice:          int32(nice),
+		StartTime:     startTime,
+		CpuTime: time.Duration(minutes)*time.Minute +
+			time.Duration(seconds)*time.Second +
+			time.Duration(hundredths)*10*time.Millisecond,
+	}, nil
+}
+
+// getProcessDetails isn't supported as Darwin has no procfs to read them
+// from.
+func getProcessDetails(pId int32) (processDetails, error) {
+	return processDetails{}, errors.New("process details are only supported on Linux")
+}
+
+// getOpenFiles isn't supported as Darwin has no procfs to read them from.
+func getOpenFiles(pId int32) ([]openFile, error) {
+	return nil, errors.New("open files are only supported on Linux")
+}
+
+// getMemoryMaps isn't supported as Darwin has no procfs to read them from.
+func getMemoryMaps(pId int32) (memoryMaps, error) {
+	return memoryMaps{}, errors.New("memory maps are only supported on Linux")
+}
+
+// setIOPriority isn't supported as Darwin has no I/O scheduling classes.
+func setIOPriority(pId int32, class int, level int) error {
+	return errors.New("I/O priorities are only supported on Linux")
 }
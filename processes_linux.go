@@ -1,67 +1,641 @@
 package main
 
 import (
-	"os/exec"
+	"bufio"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 )
 
-// getProcessesInfo, due to the version difference of the ps command between
-// Darwin and Linux based systems, uses dynamic columns widths to display the
-// results. It also uses different arguments described in the function body.
-func getProcessesInfo() []processInfo {
+// clockTicks is the USER_HZ value the kernel uses for the CPU times exposed in
+// procfs. It is fixed to 100 on every architecture supported by Go.
+const clockTicks = 100
+
+// procCollector gathers the information of the processes running in the
+// system by reading the files the kernel exposes in a procfs mount, avoiding
+// spawning a ps command each time the application updates.
+type procCollector struct {
+	// Path to the procfs mount. It is "/proc" unless a fixture directory is
+	// used instead.
+	root string
+	// Usernames resolved so far, indexed by their UID.
+	users map[string]string
+	// CPU time, in clock ticks, used by each process up to the previous
+	// collection. It is used to calculate the usage between two collections.
+	prevCpuTimes map[int32]uint64
+	// Storage I/O done by each process up to the previous collection.
+	prevIO map[int32]procIO
+	// System uptime, in seconds, at the previous collection.
+	prevUptime float64
+}
+
+// Scheduling classes of the I/O priority of a process, as defined in
+// linux/ioprio.h.
+const (
+	ioPriorityClassNone = iota
+	ioPriorityClassRealtime
+	ioPriorityClassBestEffort
+	ioPriorityClassIdle
+)
+
+const (
+	// Applies the I/O priority to a single process.
+	ioPriorityWhoProcess = 1
+	// Bits the class is shifted by in the I/O priority value.
+	ioPriorityClassShift = 13
+)
+
+// procStat holds the fields of /proc/[pid]/stat used by the application.
+type procStat struct {
+	name       string
+	state      string
+	ppid       int64
+	utime      uint64
+	stime      uint64
+	priority   int64
+	nice       int64
+	numThreads int64
+	startTime  uint64
+}
+
+// procIO holds the bytes a process read from and wrote to storage, from
+// /proc/[pid]/io.
+type procIO struct {
+	readBytes  uint64
+	writeBytes uint64
+}
+
+// procStatm holds the memory usage of a process, in bytes, from
+// /proc/[pid]/statm.
+type procStatm struct {
+	virtual  uint64
+	resident uint64
+	shared   uint64
+}
+
+// processesCollector is the collector used by getProcessesInfo.
+var processesCollector = newProcCollector("/proc")
+
+// newProcCollector returns a collector reading from the procfs mounted in
+// root.
+func newProcCollector(root string) *procCollector {
+	return &procCollector{
+		root:  root,
+		users: make(map[string]string),
+	}
+}
+
+// getProcessesInfo returns the information of every process in the system.
+// When irix is false the CPU percentage of each process is divided by the
+// number of cores, so 100% means the whole CPU is being used.
+func getProcessesInfo(irix bool) ([]processInfo, error) {
+	return processesCollector.collect(irix)
+}
+
+// collect walks the numeric directories of the procfs root and returns the
+// information of each process found. Processes that exit while being read are
+// skipped. It fails when the root can't be listed or its uptime and stat files
+// can't be read, as the CPU usage of the processes is relative to them.
+func (c *procCollector) collect(irix bool) ([]processInfo, error) {
 	var processes []processInfo
 
-	cmd := "ps"
+	entries, err := os.ReadDir(c.root)
+	if err != nil {
+		return nil, err
+	}
 
-	// Arguments for the command. Each comma adds a space to the output.
-	// ax   : Lists all processes in the system.
-	// o    : Gives a specific format to each process.
-	// pid  : Process ID.
-	// comm : Name of the process or Command.
-	// pcpu : Percentage of the CPU used by the process.
-	// prio : Priority assigned to the process.
-	// exe  : Path to the executable.
-	// args : The full command of the process with all it's arguments.
+	uptime, err := c.readUptime()
+	if err != nil {
+		return nil, err
+	}
 
-	// Each number preceded by a semicolon describes the total length of the
-	// attribute extracted. Length is then used for slicing the desired values.
-	args := []string{"-axo", "pid:10,user:50,comm:50,pcpu:4,pri:2,exe:100,args"}
-	output, err := exec.Command(cmd, args...).Output()
+	bootTime, err := c.readBootTime()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	// The result is a process's info in the given format.
-	// The first line is the column names.
-	// Removes the last newline and splits the entire string by the remaining.
-	procStrings := strings.Split(strings.TrimSuffix(string(output), "\n"), "\n")[1:]
-	for _, line := range procStrings {
-		pId, err := strconv.Atoi(strings.TrimSpace(line[:10]))
-		if err != nil {
-			panic(err)
+	cores, err := c.readCoreCount()
+	if err != nil {
+		return nil, err
+	}
+
+	// Without the total memory the memory percentages are left empty.
+	var memTotal uint64
+	if meminfo, err := c.readMemInfo(); err == nil {
+		memTotal = meminfo["MemTotal"].Value
+	}
+
+	cpuTimes := make(map[int32]uint64, len(c.prevCpuTimes))
+	ios := make(map[int32]procIO, len(c.prevIO))
+	for _, entry := range entries {
+		pId, err := strconv.ParseInt(entry.Name(), 10, 32)
+		if err != nil || !entry.IsDir() {
+			continue
 		}
-		cpuP, err := strconv.ParseFloat(strings.TrimSpace(line[113:117]), 32)
+
+		process, stat, err := c.readProcess(int32(pId))
 		if err != nil {
-			panic(err)
+			continue
 		}
-		prio, err := strconv.Atoi(strings.TrimSpace(line[118:120]))
-		if err != nil {
-			panic(err)
+
+		cpuTimes[process.PId] = stat.utime + stat.stime
+		process.StartTime = bootTime.Add(ticksToDuration(stat.startTime))
+		process.MemPercentage = memPercentage(process.Resident, memTotal)
+		process.CpuPercentage = c.cpuPercentage(process.PId, stat, uptime)
+		if !irix {
+			process.CpuPercentage /= float64(cores)
 		}
 
-		process := processInfo{
-			PId:           int32(pId),
-			User:          strings.TrimSpace(line[11:61]),
-			Name:          strings.TrimSpace(line[62:112]),
-			Priority:      int32(prio),
-			CpuPercentage: cpuP,
-			Cmdline:       strings.TrimSpace(line[222:]),
-			ExeP:          strings.TrimSpace(line[121:221]),
+		// The io file is only readable by the owner of the process, the
+		// rates of the rest are left unknown.
+		if io, err := c.readIO(filepath.Join(c.root, entry.Name())); err == nil {
+			ios[process.PId] = io
+			process.IOKnown = true
+			process.IOReadTotal = io.readBytes
+			process.IOWriteTotal = io.writeBytes
+			process.IOReadRate, process.IOWriteRate = c.ioRates(process.PId, io, uptime)
 		}
 
 		processes = append(processes, process)
 	}
 
-	return processes
+	c.prevCpuTimes = cpuTimes
+	c.prevIO = ios
+	c.prevUptime = uptime
+
+	return processes, nil
+}
+
+// cpuPercentage returns the percentage of one core used by the process since
+// the previous collection. A value of 100% means a core was fully used.
+func (c *procCollector) cpuPercentage(pId int32, stat procStat, uptime float64) float64 {
+	cpuTime := stat.utime + stat.stime
+
+	// On the first collection there is no previous sample, so the usage is
+	// averaged over the lifetime of the process as the pcpu keyword of ps.
+	if c.prevCpuTimes == nil {
+		elapsed := uptime - float64(stat.startTime)/clockTicks
+		if elapsed <= 0 {
+			return 0
+		}
+
+		return float64(cpuTime) / clockTicks / elapsed * 100
+	}
+
+	elapsed := uptime - c.prevUptime
+	if elapsed <= 0 {
+		return 0
+	}
+
+	// A process not seen before started after the previous collection, so
+	// all of its CPU time was used in between. The same happens when the PID
+	// was reused by a newer process.
+	prev, ok := c.prevCpuTimes[pId]
+	if !ok || prev > cpuTime {
+		prev = 0
+	}
+
+	return float64(cpuTime-prev) / clockTicks / elapsed * 100
+}
+
+// ioRates returns the bytes per second the process read and wrote since the
+// previous collection.
+func (c *procCollector) ioRates(pId int32, io procIO, uptime float64) (float64, float64) {
+	elapsed := uptime - c.prevUptime
+	if c.prevIO == nil || elapsed <= 0 {
+		return 0, 0
+	}
+
+	// As with the CPU time, a process not seen before did all of its I/O
+	// after the previous collection. One seen before without its I/O, as
+	// when its io file couldn't be read, has no rates until it is read twice.
+	prev, ok := c.prevIO[pId]
+	if _, seen := c.prevCpuTimes[pId]; !ok && seen {
+		return 0, 0
+	}
+	if prev.readBytes > io.readBytes || prev.writeBytes > io.writeBytes {
+		prev = procIO{}
+	}
+
+	return float64(io.readBytes-prev.readBytes) / elapsed, float64(io.writeBytes-prev.writeBytes) / elapsed
+}
+
+// readProcess builds the information of a single process. The parsed stat
+// file is also returned for calculating its CPU usage.
+func (c *procCollector) readProcess(pId int32) (processInfo, procStat, error) {
+	dir := filepath.Join(c.root, strconv.Itoa(int(pId)))
+
+	stat, err := c.readStat(dir)
+	if err != nil {
+		return processInfo{}, procStat{}, err
+	}
+
+	statm, err := c.readStatm(dir)
+	if err != nil {
+		return processInfo{}, procStat{}, err
+	}
+
+	uid, err := c.readUid(dir)
+	if err != nil {
+		return processInfo{}, procStat{}, err
+	}
+
+	cmdline, err := os.ReadFile(filepath.Join(dir, "cmdline"))
+	if err != nil {
+		return processInfo{}, procStat{}, err
+	}
+	cmd := strings.TrimSpace(strings.ReplaceAll(string(cmdline), "\x00", " "))
+	// Kernel threads have no command line. ps shows them with their name
+	// between brackets.
+	if cmd == "" {
+		cmd = fmt.Sprintf("[%s]", stat.name)
+	}
+
+	// The executable link is only readable by the owner of the process.
+	exeP, _ := os.Readlink(filepath.Join(dir, "exe"))
+
+	// Kernels without cgroups leave the processes without one.
+	cgroup, _ := c.readCgroup(dir)
+
+	process := processInfo{
+		PId:      pId,
+		PPId:     int32(stat.ppid),
+		User:     c.username(uid),
+		Name:     stat.name,
+		Priority: int32(stat.priority),
+		Cmdline:  cmd,
+		ExeP:     exeP,
+		Resident: statm.resident,
+		Virtual:  statm.virtual,
+		Shared:   statm.shared,
+		State:    stat.state,
+		Threads:  int32(stat.numThreads),
+		Nice:     int32(stat.nice),
+		CpuTime:  ticksToDuration(stat.utime + stat.stime),
+
+		Cgroup:    cgroup,
+		Container: cgroupLabel(cgroup),
+	}
+
+	return process, stat, nil
+}
+
+// readStat parses the /proc/[pid]/stat file of the process in dir.
+func (c *procCollector) readStat(dir string) (procStat, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return procStat{}, err
+	}
+
+	// The name of the process is enclosed in parentheses and may contain
+	// spaces or parentheses itself, so the last closing one delimits it.
+	line := string(data)
+	start := strings.IndexByte(line, '(')
+	end := strings.LastIndexByte(line, ')')
+	if start < 0 || end < start {
+		return procStat{}, fmt.Errorf("malformed stat file in %s", dir)
+	}
+
+	// fields[0] is the third field described in proc(5), the state.
+	fields := strings.Fields(line[end+1:])
+	if len(fields) < 20 {
+		return procStat{}, fmt.Errorf("malformed stat file in %s", dir)
+	}
+
+	stat := procStat{
+		name:  line[start+1 : end],
+		state: fields[0],
+	}
+	if stat.ppid, err = strconv.ParseInt(fields[1], 10, 32); err != nil {
+		return procStat{}, err
+	}
+	if stat.utime, err = strconv.ParseUint(fields[11], 10, 64); err != nil {
+		return procStat{}, err
+	}
+	if stat.stime, err = strconv.ParseUint(fields[12], 10, 64); err != nil {
+		return procStat{}, err
+	}
+	if stat.priority, err = strconv.ParseInt(fields[15], 10, 64); err != nil {
+		return procStat{}, err
+	}
+	if stat.nice, err = strconv.ParseInt(fields[16], 10, 64); err != nil {
+		return procStat{}, err
+	}
+	if stat.numThreads, err = strconv.ParseInt(fields[17], 10, 64); err != nil {
+		return procStat{}, err
+	}
+	if stat.startTime, err = strconv.ParseUint(fields[19], 10, 64); err != nil {
+		return procStat{}, err
+	}
+
+	return stat, nil
+}
+
+// readStatm parses the /proc/[pid]/statm file of the process in dir. The
+// values in the file are expressed in pages.
+func (c *procCollector) readStatm(dir string) (procStatm, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "statm"))
+	if err != nil {
+		return procStatm{}, err
+	}
+
+	// size resident shared text lib data dt
+	fields := strings.Fields(string(data))
+	if len(fields) < 3 {
+		return procStatm{}, fmt.Errorf("malformed statm file in %s", dir)
+	}
+
+	var pages [3]uint64
+	for i := range pages {
+		if pages[i], err = strconv.ParseUint(fields[i], 10, 64); err != nil {
+			return procStatm{}, err
+		}
+	}
+
+	pageSize := uint64(os.Getpagesize())
+	statm := procStatm{
+		virtual:  pages[0] * pageSize,
+		resident: pages[1] * pageSize,
+		shared:   pages[2] * pageSize,
+	}
+
+	return statm, nil
+}
+
+// readIO parses the /proc/[pid]/io file of the process in dir.
+func (c *procCollector) readIO(dir string) (procIO, error) {
+	f, err := os.Open(filepath.Join(dir, "io"))
+	if err != nil {
+		return procIO{}, err
+	}
+	defer f.Close()
+
+	var io procIO
+	var found int
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+
+		var value *uint64
+		switch fields[0] {
+		case "read_bytes:":
+			value = &io.readBytes
+		case "write_bytes:":
+			value = &io.writeBytes
+		default:
+			continue
+		}
+
+		if *value, err = strconv.ParseUint(fields[1], 10, 64); err != nil {
+			return procIO{}, err
+		}
+		found++
+	}
+
+	if err := scanner.Err(); err != nil {
+		return procIO{}, err
+	}
+
+	if found < 2 {
+		return procIO{}, fmt.Errorf("malformed io file in %s", dir)
+	}
+
+	return io, nil
+}
+
+// readUid returns the effective UID of the process in dir from its status
+// file.
+func (c *procCollector) readUid(dir string) (string, error) {
+	f, err := os.Open(filepath.Join(dir, "status"))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Uid: real effective saved filesystem
+		if fields := strings.Fields(scanner.Text()); len(fields) > 2 && fields[0] == "Uid:" {
+			return fields[2], nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	return "", fmt.Errorf("no Uid line in the status file in %s", dir)
+}
+
+// readCgroup returns the path of the process in dir within the cgroup
+// hierarchy, from its cgroup file. The path in the unified (v2) hierarchy is
+// preferred. On hosts only mounting v1 hierarchies the one of the systemd
+// hierarchy is used, or else the one of the memory controller.
+func (c *procCollector) readCgroup(dir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "cgroup"))
+	if err != nil {
+		return "", err
+	}
+
+	var systemdPath, memoryPath string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		// hierarchy-ID:controller-list:cgroup-path
+		fields := strings.SplitN(line, ":", 3)
+		if len(fields) < 3 {
+			continue
+		}
+
+		switch controllers := strings.Split(fields[1], ","); {
+		case fields[0] == "0" && fields[1] == "":
+			return fields[2], nil
+		case slices.Contains(controllers, "name=systemd"):
+			systemdPath = fields[2]
+		case slices.Contains(controllers, "memory"):
+			memoryPath = fields[2]
+		}
+	}
+
+	if systemdPath != "" {
+		return systemdPath, nil
+	}
+	if memoryPath != "" {
+		return memoryPath, nil
+	}
+
+	return "", fmt.Errorf("no cgroup found in the cgroup file in %s", dir)
+}
+
+// readUptime returns the seconds passed since the system booted.
+func (c *procCollector) readUptime() (float64, error) {
+	data, err := os.ReadFile(filepath.Join(c.root, "uptime"))
+	if err != nil {
+		return 0, err
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("malformed uptime file in %s", c.root)
+	}
+
+	return strconv.ParseFloat(fields[0], 64)
+}
+
+// readBootTime returns the time the system booted from the btime line of the
+// stat file in the procfs root.
+func (c *procCollector) readBootTime() (time.Time, error) {
+	f, err := os.Open(filepath.Join(c.root, "stat"))
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) > 1 && fields[0] == "btime" {
+			seconds, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return time.Time{}, err
+			}
+
+			return time.Unix(seconds, 0), nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Time{}, fmt.Errorf("no btime line in the stat file in %s", c.root)
+}
+
+// readCoreCount returns the number of cores online, from the cpuN lines of
+// the stat file in the procfs root. Unlike the CPUs this process may run on,
+// it doesn't shrink when the process is bound to some of them.
+func (c *procCollector) readCoreCount() (int, error) {
+	f, err := os.Open(filepath.Join(c.root, "stat"))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	cores := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		name, _, _ := strings.Cut(scanner.Text(), " ")
+		if _, err := strconv.Atoi(strings.TrimPrefix(name, "cpu")); err == nil && strings.HasPrefix(name, "cpu") {
+			cores++
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	if cores == 0 {
+		return 0, fmt.Errorf("no cpuN line in the stat file in %s", c.root)
+	}
+
+	return cores, nil
+}
+
+// ticksToDuration converts an amount of clock ticks into a duration.
+func ticksToDuration(ticks uint64) time.Duration {
+	return time.Duration(ticks) * time.Second / clockTicks
+}
+
+// username resolves the given UID, caching the result. When the UID has no
+// user assigned the UID itself is returned, as ps does.
+func (c *procCollector) username(uid string) string {
+	if name, ok := c.users[uid]; ok {
+		return name
+	}
+
+	name := uid
+	if u, err := user.LookupId(uid); err == nil {
+		name = u.Username
+	}
+	c.users[uid] = name
+
+	return name
+}
+
+// getProcessDetails returns the details of the process with the given ID.
+// It fails when the process doesn't exist anymore.
+func getProcessDetails(pId int32) (processDetails, error) {
+	return processesCollector.readDetails(pId)
+}
+
+// readDetails reads the details of a process from its procfs directory.
+func (c *procCollector) readDetails(pId int32) (processDetails, error) {
+	dir := filepath.Join(c.root, strconv.Itoa(int(pId)))
+	details := processDetails{PId: pId}
+
+	status, err := os.ReadFile(filepath.Join(dir, "status"))
+	if err != nil {
+		return processDetails{}, err
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(status)), "\n") {
+		// Name:\tvalue
+		name, value, _ := strings.Cut(line, ":")
+		details.Status = append(details.Status, processField{Name: name, Value: strings.TrimSpace(value)})
+	}
+
+	if cmdline, err := os.ReadFile(filepath.Join(dir, "cmdline")); err == nil {
+		details.Args = splitNul(cmdline)
+	}
+
+	environ, err := os.ReadFile(filepath.Join(dir, "environ"))
+	details.Environ, details.EnvironErr = splitNul(environ), err
+
+	// The links are only readable by the owner of the process.
+	details.Cwd, _ = os.Readlink(filepath.Join(dir, "cwd"))
+	details.Exe, _ = os.Readlink(filepath.Join(dir, "exe"))
+
+	if cgroup, err := os.ReadFile(filepath.Join(dir, "cgroup")); err == nil {
+		details.Cgroups = strings.Split(strings.TrimSpace(string(cgroup)), "\n")
+	}
+
+	if entries, err := os.ReadDir(filepath.Join(dir, "ns")); err == nil {
+		for _, entry := range entries {
+			if link, err := os.Readlink(filepath.Join(dir, "ns", entry.Name())); err == nil {
+				details.Namespaces = append(details.Namespaces, processField{Name: entry.Name(), Value: link})
+			}
+		}
+	}
+
+	if limits, err := os.ReadFile(filepath.Join(dir, "limits")); err == nil {
+		details.Limits = strings.Split(strings.TrimRight(string(limits), "\n"), "\n")
+	}
+
+	return details, nil
+}
+
+// splitNul splits the NUL separated values of a procfs file, like cmdline.
+func splitNul(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+
+	return strings.Split(strings.TrimRight(string(data), "\x00"), "\x00")
+}
+
+// setIOPriority changes the I/O scheduling class and level, from 0 (highest)
+// to 7 (lowest), of the process with the given ID.
+func setIOPriority(pId int32, class int, level int) error {
+	ioprio := class<<ioPriorityClassShift | level
+	_, _, errno := syscall.Syscall(syscall.SYS_IOPRIO_SET, ioPriorityWhoProcess, uintptr(pId), uintptr(ioprio))
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
 }
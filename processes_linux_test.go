@@ -0,0 +1,298 @@
+package main
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fixtureProc is a procfs tree with an init process, a process whose name
+// has spaces and parentheses and a kernel thread.
+const fixtureProc = "testdata/proc"
+
+func TestProcCollectorReadFiles(t *testing.T) {
+	c := newProcCollector(fixtureProc)
+	pageSize := uint64(os.Getpagesize())
+
+	tests := []struct {
+		name  string
+		pId   string
+		stat  procStat
+		statm procStatm
+		io    procIO
+		ioErr bool
+		uid   string
+	}{
+		{
+			name: "init",
+			pId:  "1",
+			stat: procStat{name: "systemd", state: "S", ppid: 0, utime: 150, stime: 50,
+				priority: 20, nice: 0, numThreads: 1, startTime: 1000},
+			statm: procStatm{virtual: 41500 * pageSize, resident: 3000 * pageSize, shared: 2000 * pageSize},
+			// The io file is missing, as when it belongs to another user.
+			ioErr: true,
+			uid:   "0",
+		},
+		{
+			name: "name with spaces and parentheses",
+			pId:  "42",
+			stat: procStat{name: "my (weird) proc", state: "R", ppid: 1, utime: 300, stime: 100,
+				priority: 39, nice: 19, numThreads: 4, startTime: 3000},
+			statm: procStatm{virtual: 2441 * pageSize, resident: 250 * pageSize, shared: 100 * pageSize},
+			io:    procIO{readBytes: 4096, writeBytes: 1024},
+			// The effective UID, not the real one.
+			uid: "1001",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(fixtureProc, tt.pId)
+
+			stat, err := c.readStat(dir)
+			if err != nil {
+				t.Fatalf("readStat: %v", err)
+			}
+			if stat != tt.stat {
+				t.Errorf("readStat = %+v, want %+v", stat, tt.stat)
+			}
+
+			statm, err := c.readStatm(dir)
+			if err != nil {
+				t.Fatalf("readStatm: %v", err)
+			}
+			if statm != tt.statm {
+				t.Errorf("readStatm = %+v, want %+v", statm, tt.statm)
+			}
+
+			io, err := c.readIO(dir)
+			if (err != nil) != tt.ioErr {
+				t.Fatalf("readIO error = %v, want error %v", err, tt.ioErr)
+			}
+			if io != tt.io {
+				t.Errorf("readIO = %+v, want %+v", io, tt.io)
+			}
+
+			uid, err := c.readUid(dir)
+			if err != nil {
+				t.Fatalf("readUid: %v", err)
+			}
+			if uid != tt.uid {
+				t.Errorf("readUid = %q, want %q", uid, tt.uid)
+			}
+		})
+	}
+}
+
+func TestProcCollectorCpuPercentage(t *testing.T) {
+	// 400 ticks of CPU time, started 30 seconds after the boot.
+	stat := procStat{utime: 300, stime: 100, startTime: 3000}
+
+	tests := []struct {
+		name         string
+		prevCpuTimes map[int32]uint64
+		prevUptime   float64
+		uptime       float64
+		want         float64
+	}{
+		{
+			name:   "first collection averages over the lifetime",
+			uptime: 110,
+			want:   5,
+		},
+		{
+			name:         "usage since the previous collection",
+			prevCpuTimes: map[int32]uint64{42: 300},
+			prevUptime:   100,
+			uptime:       110,
+			want:         10,
+		},
+		{
+			name:         "process not seen before",
+			prevCpuTimes: map[int32]uint64{1: 200},
+			prevUptime:   100,
+			uptime:       110,
+			want:         40,
+		},
+		{
+			name:         "reused PID",
+			prevCpuTimes: map[int32]uint64{42: 500},
+			prevUptime:   100,
+			uptime:       110,
+			want:         40,
+		},
+		{
+			name:         "no time elapsed",
+			prevCpuTimes: map[int32]uint64{42: 300},
+			prevUptime:   110,
+			uptime:       110,
+			want:         0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newProcCollector(fixtureProc)
+			c.prevCpuTimes = tt.prevCpuTimes
+			c.prevUptime = tt.prevUptime
+
+			if got := c.cpuPercentage(42, stat, tt.uptime); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("cpuPercentage = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcCollectorIORates(t *testing.T) {
+	io := procIO{readBytes: 4096, writeBytes: 1024}
+
+	tests := []struct {
+		name         string
+		prevCpuTimes map[int32]uint64
+		prevIO       map[int32]procIO
+		readRate     float64
+		writeRate    float64
+	}{
+		{
+			name:      "rates since the previous collection",
+			prevIO:    map[int32]procIO{42: {readBytes: 2048, writeBytes: 1024}},
+			readRate:  204.8,
+			writeRate: 0,
+		},
+		{
+			name:         "process not seen before",
+			prevCpuTimes: map[int32]uint64{1: 200},
+			prevIO:       map[int32]procIO{1: {}},
+			readRate:     409.6,
+			writeRate:    102.4,
+		},
+		{
+			name:         "io file not read in the previous collection",
+			prevCpuTimes: map[int32]uint64{42: 300},
+			prevIO:       map[int32]procIO{},
+		},
+		{
+			name:      "reused PID",
+			prevIO:    map[int32]procIO{42: {readBytes: 8192}},
+			readRate:  409.6,
+			writeRate: 102.4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newProcCollector(fixtureProc)
+			c.prevCpuTimes = tt.prevCpuTimes
+			c.prevIO = tt.prevIO
+			c.prevUptime = 100
+
+			read, write := c.ioRates(42, io, 110)
+			if math.Abs(read-tt.readRate) > 1e-9 || math.Abs(write-tt.writeRate) > 1e-9 {
+				t.Errorf("ioRates = %v, %v, want %v, %v", read, write, tt.readRate, tt.writeRate)
+			}
+		})
+	}
+}
+
+func TestProcCollectorCollect(t *testing.T) {
+	c := newProcCollector(fixtureProc)
+
+	processes, err := c.collect(true)
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+
+	byPId := make(map[int32]processInfo)
+	for _, p := range processes {
+		byPId[p.PId] = p
+	}
+	if len(byPId) != 3 {
+		t.Fatalf("collect returned %d processes, want 3", len(byPId))
+	}
+
+	weird := byPId[42]
+	if weird.Name != "my (weird) proc" || weird.Cmdline != "./weird --flag" {
+		t.Errorf("process 42 = %q %q, want its name and command line", weird.Name, weird.Cmdline)
+	}
+	// 400 ticks of CPU time in the 80 seconds since it started.
+	if math.Abs(weird.CpuPercentage-5) > 1e-9 {
+		t.Errorf("process 42 CPU = %v, want 5", weird.CpuPercentage)
+	}
+	if want := time.Unix(1700000000, 0).Add(30 * time.Second); !weird.StartTime.Equal(want) {
+		t.Errorf("process 42 started at %v, want %v", weird.StartTime, want)
+	}
+	if weird.Container != "docker:0123456789ab" {
+		t.Errorf("process 42 container = %q, want docker:0123456789ab", weird.Container)
+	}
+	if !weird.IOKnown || byPId[1].IOKnown {
+		t.Errorf("IOKnown = %v for process 42 and %v for 1, want only 42", weird.IOKnown, byPId[1].IOKnown)
+	}
+	if want := float64(weird.Resident) / (8000000 * 1024) * 100; math.Abs(weird.MemPercentage-want) > 1e-9 {
+		t.Errorf("process 42 MEM%% = %v, want %v", weird.MemPercentage, want)
+	}
+	if cmd := byPId[2].Cmdline; cmd != "[kthreadd]" {
+		t.Errorf("kernel thread command line = %q, want [kthreadd]", cmd)
+	}
+}
+
+func TestProcCollectorCollectNotIrix(t *testing.T) {
+	irix, err := newProcCollector(fixtureProc).collect(true)
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	processes, err := newProcCollector(fixtureProc).collect(false)
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+
+	// The fixture has 2 cores, whatever the CPUs the test may run on.
+	for i, process := range processes {
+		if want := irix[i].CpuPercentage / 2; math.Abs(process.CpuPercentage-want) > 1e-9 {
+			t.Errorf("process %d CPU = %v, want %v", process.PId, process.CpuPercentage, want)
+		}
+	}
+}
+
+func TestProcCollectorCollectErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+	}{
+		{"missing root", nil},
+		{"missing uptime", map[string]string{"stat": "cpu0 0 0 0 0 0 0 0 0 0 0\nbtime 1700000000\n"}},
+		{"missing btime", map[string]string{"uptime": "110.00 200.00\n", "stat": "cpu0 0 0 0 0 0 0 0 0 0 0\n"}},
+		{"missing cores", map[string]string{"uptime": "110.00 200.00\n", "stat": "cpu 0 0 0 0 0 0 0 0 0 0\nbtime 1700000000\n"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := filepath.Join(t.TempDir(), "proc")
+			if tt.files != nil {
+				if err := os.Mkdir(root, 0o755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if _, err := newProcCollector(root).collect(true); err == nil {
+				t.Error("collect succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestMemPercentage(t *testing.T) {
+	if got := memPercentage(512, 2048); got != 25 {
+		t.Errorf("memPercentage(512, 2048) = %v, want 25", got)
+	}
+	// An unknown total leaves the percentage empty rather than infinite.
+	if got := memPercentage(512, 0); got != 0 {
+		t.Errorf("memPercentage(512, 0) = %v, want 0", got)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseProcessFilter(t *testing.T) {
+	postgres := processInfo{PId: 10, User: "postgres", Name: "postgres", Cmdline: "postgres -D /var/lib/pgsql",
+		CpuPercentage: 12.5, MemPercentage: 2.5}
+	shell := processInfo{PId: 20, User: "alice", Name: "bash", Cmdline: "bash --login",
+		CpuPercentage: 0.5, MemPercentage: 0.1}
+	env := processInfo{PId: 30, User: "alice", Name: "env", Cmdline: "env a=b make",
+		CpuPercentage: 40, MemPercentage: 7}
+
+	tests := []struct {
+		query string
+		// Whether postgres, shell and env match, in that order.
+		want [3]bool
+	}{
+		{"user:postgres", [3]bool{true, false, false}},
+		{"USER:Alice", [3]bool{false, true, true}},
+		{"cpu>10", [3]bool{true, false, true}},
+		{"mem<=2.5", [3]bool{true, true, false}},
+		{"pid!=20", [3]bool{true, false, true}},
+		// The = of the expression isn't taken for a comparison.
+		{"re:a=b", [3]bool{false, false, true}},
+		{"re:^(bash|env)$", [3]bool{false, true, true}},
+		// Every term has to match.
+		{"user:alice cpu>10", [3]bool{false, false, true}},
+		// An unknown field is searched as it is in the name and command.
+		{"https://example.com", [3]bool{false, false, false}},
+		{"login", [3]bool{false, true, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			filter, err := parseProcessFilter(tt.query)
+			if err != nil {
+				t.Fatalf("parseProcessFilter: %v", err)
+			}
+			if !filter.active() {
+				t.Fatal("filter isn't active")
+			}
+
+			for i, p := range []processInfo{postgres, shell, env} {
+				if got := filter.match(p); got != tt.want[i] {
+					t.Errorf("match(%s) = %v, want %v", p.Name, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseProcessFilterEmpty(t *testing.T) {
+	filter, err := parseProcessFilter("   ")
+	if err != nil {
+		t.Fatalf("parseProcessFilter: %v", err)
+	}
+	if filter.active() {
+		t.Error("empty filter is active")
+	}
+	if !filter.match(processInfo{Name: "bash"}) {
+		t.Error("empty filter doesn't match every process")
+	}
+}
+
+func TestParseProcessFilterErrors(t *testing.T) {
+	for _, query := range []string{"re:(unclosed", "cpu>high", "mem:lots", "user:root cpu>=x"} {
+		if _, err := parseProcessFilter(query); err == nil {
+			t.Errorf("parseProcessFilter(%q) succeeded, want an error", query)
+		}
+	}
+}
+
+func TestGroupProcesses(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	processes := []processInfo{
+		{PId: 100, User: "root", Container: "docker:0123456789ab", Cgroup: "/system.slice/docker-0123.scope/init",
+			CpuPercentage: 10, MemPercentage: 1, Resident: 100, Threads: 2, StartTime: start.Add(time.Minute)},
+		{PId: 200, User: "alice", Name: "bash", Container: "session-2.scope"},
+		{PId: 101, User: "www", Container: "docker:0123456789ab", Cgroup: "/system.slice/docker-0123.scope/app",
+			CpuPercentage: 30, MemPercentage: 4, Resident: 400, Threads: 8, StartTime: start, IOKnown: true},
+		{PId: 102, User: "www", Container: "docker:0123456789ab", Cgroup: "/system.slice/docker-0123.scope/app",
+			CpuPercentage: 5, MemPercentage: 0.5, Resident: 50, Threads: 1, StartTime: start.Add(time.Hour)},
+	}
+
+	groups := groupProcesses(processes, "CpuPercentage", true)
+	if len(groups) != 2 {
+		t.Fatalf("groupProcesses returned %d groups, want 2", len(groups))
+	}
+
+	// The usage of the processes of a container is added up, and only the
+	// fields they share are kept.
+	want := processGroup{count: 3, process: processInfo{Container: "docker:0123456789ab",
+		Cgroup: "/system.slice/docker-0123.scope", CpuPercentage: 45, MemPercentage: 5.5, Resident: 550,
+		Threads: 11, StartTime: start, IOKnown: true}}
+	if !reflect.DeepEqual(groups[0], want) {
+		t.Errorf("container group =\n%+v\nwant\n%+v", groups[0], want)
+	}
+
+	// A group of one process keeps its user, but not its process ID.
+	if g := groups[1]; g.count != 1 || g.process.User != "alice" || g.process.PId != 0 {
+		t.Errorf("session group = %+v, want alice's process without its ID", g)
+	}
+}
+
+func TestGroupViewSelection(t *testing.T) {
+	m := model{
+		Processes: []processInfo{
+			{PId: 100, Container: "docker:0123456789ab"},
+			{PId: 101, Container: "docker:0123456789ab"},
+		},
+		groupView: true,
+		tagged:    make(map[int32]struct{}),
+	}
+	m.processesTable = newProcessesTable(m, 10).WithRows(generateProcessesTableRows(m))
+	if rows := len(m.processesTable.GetVisibleRows()); rows != 1 {
+		t.Fatalf("the group view has %d rows, want 1", rows)
+	}
+
+	// The row of a group has no process ID, so there is nothing to act on
+	// or to tag.
+	if pIds := m.targetProcesses(); len(pIds) != 0 {
+		t.Errorf("targetProcesses = %v on a group, want none", pIds)
+	}
+	if m = m.toggleTag(); len(m.tagged) != 0 {
+		t.Errorf("toggleTag tagged %v on a group, want nothing", m.tagged)
+	}
+}
@@ -0,0 +1,37 @@
+//go:build linux || darwin
+
+package main
+
+import (
+	"syscall"
+)
+
+// signalOption is a signal the user can send to the processes.
+type signalOption struct {
+	name   string
+	signal syscall.Signal
+}
+
+// signalOptions are the signals listed in the signal menu, the first one
+// being the default choice.
+var signalOptions = []signalOption{
+	{"SIGTERM", syscall.SIGTERM},
+	{"SIGKILL", syscall.SIGKILL},
+	{"SIGHUP", syscall.SIGHUP},
+	{"SIGINT", syscall.SIGINT},
+	{"SIGQUIT", syscall.SIGQUIT},
+	{"SIGSTOP", syscall.SIGSTOP},
+	{"SIGCONT", syscall.SIGCONT},
+	{"SIGUSR1", syscall.SIGUSR1},
+	{"SIGUSR2", syscall.SIGUSR2},
+}
+
+// sendSignal sends the signal to the process with the given ID.
+func sendSignal(pId int32, sig syscall.Signal) error {
+	return syscall.Kill(int(pId), sig)
+}
+
+// setNice changes the nice value of the process with the given ID.
+func setNice(pId int32, nice int) error {
+	return syscall.Setpriority(syscall.PRIO_PROCESS, int(pId), nice)
+}
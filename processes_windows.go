@@ -9,6 +9,7 @@ func extractProcessesInfo() []processInfo {
 	var processes []processInfo
 
 	for _, p := range ps {
+		ppid, _ := p.Ppid()
 		u, _ := p.Username()
 		n, _ := p.Name()
 		prio, _ := p.Nice()
@@ -18,6 +19,7 @@ func extractProcessesInfo() []processInfo {
 
 		processInfo := processInfo{
 			PId:           p.Pid,
+			PPId:          ppid,
 			User:          u,
 			Name:          n,
 			Priority:      prio,
@@ -1,9 +1,16 @@
 package main
 
 import (
-	"github.com/shirou/gopsutil/v3/cpu"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
 	"github.com/shirou/gopsutil/v3/disk"
 	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/shirou/gopsutil/v3/net"
 )
 
 const (
@@ -16,47 +23,497 @@ const (
 )
 
 var (
-	// Displaying relevant file systems. Irrelevant may be
+	// Prefixes of the names of the loopback and virtual network interfaces,
+	// like the ones created by Docker or libvirt.
+	virtualInterfacePrefixes = []string{"lo", "veth", "docker", "br-", "virbr", "vnet"}
+
+	// Displaying relevant file systems by default. Irrelevant may be
 	// read only (like squashfs), tracing (like tracefs), etc.
-	fsFilter = map[string]struct{}{
-		"ext4":    {},
-		"vfat":    {},
-		"fuseblk": {},
-		"ntfs":    {},
-		"fat32":   {},
-		"apfs":    {},
-	}
+	defaultFsTypes = []string{"ext4", "vfat", "fuseblk", "ntfs", "fat32", "apfs", "btrfs", "xfs", "zfs"}
 )
 
+// diskFilter decides which file systems are shown in the disks table.
+type diskFilter struct {
+	// File system types shown, every type when nil.
+	types map[string]struct{}
+	// File system types hidden even when they are in types.
+	excludeTypes map[string]struct{}
+	// Patterns the device and the mount path must match and must not match.
+	// Nil patterns aren't checked.
+	device           *regexp.Regexp
+	excludeDevice    *regexp.Regexp
+	mountPath        *regexp.Regexp
+	excludeMountPath *regexp.Regexp
+}
+
+// cpuTimes holds the time, in seconds, a CPU spent in each state.
+type cpuTimes struct {
+	User   float64
+	Nice   float64
+	System float64
+	Idle   float64
+	IOWait float64
+	// Time serving both hardware and software interrupts.
+	Irq float64
+	// Time stolen by the hypervisor to run other virtual machines.
+	Steal float64
+}
+
+// cpuUsage holds the percentage of time a CPU spent in each state, except
+// idle, over an interval.
+type cpuUsage struct {
+	User   float64
+	Nice   float64
+	System float64
+	IOWait float64
+	Irq    float64
+	Steal  float64
+}
+
+// networkInfo holds the traffic of a network interface, per second, since
+// the previous update.
+type networkInfo struct {
+	Interface   string
+	RecvRate    float64
+	SentRate    float64
+	PacketsRecv float64
+	PacketsSent float64
+	// Errors and dropped packets, both received and sent.
+	Errors float64
+	Drops  float64
+}
+
+// networkCollector calculates the traffic of the network interfaces from the
+// difference between the counters read on each collection.
+type networkCollector struct {
+	prev     map[string]net.IOCountersStat
+	prevTime time.Time
+}
+
+// networksCollector is the collector used by getNetworkInfo.
+var networksCollector = &networkCollector{}
+
+// cpuCollector calculates the usage of the CPU from the difference between
+// the times read on each collection.
+type cpuCollector struct {
+	prevAll   cpuTimes
+	prevCores []cpuTimes
+}
+
+// cpusCollector is the collector used by getCpuInfo.
+var cpusCollector = &cpuCollector{}
+
+// systemInfo holds the figures shown in the header.
+type systemInfo struct {
+	// Load averages over 1, 5 and 15 minutes.
+	Load1  float64
+	Load5  float64
+	Load15 float64
+	Uptime time.Duration
+	// Current frequency of the cores that report it, ordered by core.
+	Frequencies []cpuFrequency
+	// Pressure stall information of the CPU, the memory and the I/O, empty
+	// when the kernel doesn't expose it.
+	Pressure []pressure
+}
+
+// cpuFrequency is the current frequency of a core.
+type cpuFrequency struct {
+	// Index of the core, as in its cpuN name.
+	Core int
+	MHz  float64
+}
+
+// pressureStall is the share of the time, in percent, some or all tasks were
+// stalled waiting for a resource over the last 10, 60 and 300 seconds.
+type pressureStall struct {
+	Avg10  float64
+	Avg60  float64
+	Avg300 float64
+}
+
+// pressure is the pressure stall information of a resource.
+type pressure struct {
+	// cpu, memory or io.
+	Resource string
+	// Time at least one task was stalled.
+	Some pressureStall
+	// Time all the non idle tasks were stalled at once. Older kernels don't
+	// report it for the CPU.
+	Full    pressureStall
+	HasFull bool
+}
+
 type memoryInfo struct {
-	Total       float64
-	Used        float64
+	// Sizes in bytes.
+	Total       uint64
+	Used        uint64
 	UsedPercent float64
+	// Parts of the memory not counted as used, only filled for the virtual
+	// memory. Shared is part of Cached.
+	Buffers   uint64
+	Cached    uint64
+	Shared    uint64
+	Available uint64
+}
+
+// memoryDetail is a value of the kernel's memory accounting, like the Dirty
+// or HugePages_Total lines of /proc/meminfo.
+type memoryDetail struct {
+	Name  string
+	Value uint64
+	// Whether the value is a size in bytes, otherwise it is a count, like
+	// the amount of huge pages.
+	IsSize bool
+}
+
+// zramInfo is the usage of a compressed block device in RAM. Sizes are in
+// bytes.
+type zramInfo struct {
+	Device string
+	// Size of the device as seen by its users.
+	DiskSize uint64
+	// Size of the data stored, before and after compressing it.
+	OriginalSize   uint64
+	CompressedSize uint64
+	// Memory taken by the device, including its overhead.
+	MemoryUsed uint64
+}
+
+// memoryDetails holds the memory accounting shown in the memory details
+// view.
+type memoryDetails struct {
+	Entries []memoryDetail
+	Zram    []zramInfo
 }
 
 type diskInfo struct {
 	FsType    string
 	Device    string
 	MountPath string
-	TotalSize float64
-	FreeSize  float64
-	UsedSize  float64
+	// Sizes in bytes.
+	TotalSize uint64
+	FreeSize  uint64
+	UsedSize  uint64
+	// Percentage of the size used by non-root users, as df reports it.
+	UsedPercent float64
+	InodesTotal uint64
+	InodesUsed  uint64
+	InodesFree  uint64
+	diskIO
+}
+
+// diskIO holds the activity of a block device since the previous update.
+type diskIO struct {
+	// Bytes read and written per second.
+	ReadRate  float64
+	WriteRate float64
+	// Reads and writes completed per second.
+	IOPS float64
+	// Percentage of the time the device was busy.
+	Utilization float64
+	// Average time, in milliseconds, a read or write took to be served.
+	Await float64
+}
+
+// diskIOCollector calculates the activity of the block devices from the
+// difference between the counters read on each collection.
+type diskIOCollector struct {
+	prev     map[string]disk.IOCountersStat
+	prevTime time.Time
+}
+
+// diskCountersReset reports whether any of the counters of a disk went
+// backwards since the previous sample, as when the device is reattached or a
+// counter wraps around. Their difference would underflow otherwise.
+func diskCountersReset(counter, prev disk.IOCountersStat) bool {
+	return counter.ReadCount < prev.ReadCount || counter.WriteCount < prev.WriteCount ||
+		counter.ReadBytes < prev.ReadBytes || counter.WriteBytes < prev.WriteBytes ||
+		counter.ReadTime < prev.ReadTime || counter.WriteTime < prev.WriteTime ||
+		counter.IoTime < prev.IoTime
 }
 
+// disksIOCollector is the collector used by getDiskInfo.
+var disksIOCollector = &diskIOCollector{}
+
 type processInfo struct {
 	PId           int32
+	PPId          int32
 	User          string
 	Name          string
 	Priority      int32
 	CpuPercentage float64
 	Cmdline       string
 	ExeP          string
+	// Resident, virtual and shared memory in bytes.
+	Resident uint64
+	Virtual  uint64
+	Shared   uint64
+	// Percentage of the system's memory taken by the resident memory.
+	MemPercentage float64
+	// State of the process as a single letter, like R for running or Z for
+	// zombie.
+	State   string
+	Threads int32
+	Nice    int32
+	// Time the process started.
+	StartTime time.Time
+	// Time the process has spent in the CPU, both in user and kernel mode.
+	CpuTime time.Duration
+	// Bytes read from and written to storage, per second since the previous
+	// update and in total. They are only known when IOKnown is set, as the
+	// I/O of the processes of other users can't be read.
+	IOReadRate   float64
+	IOWriteRate  float64
+	IOReadTotal  uint64
+	IOWriteTotal uint64
+	IOKnown      bool
+	// Path of the process in the cgroup hierarchy and the container, pod or
+	// systemd unit derived from it. See cgroupLabel.
+	Cgroup    string
+	Container string
+}
+
+// processField is a named value of a process, like a line of its status
+// file or one of its namespaces.
+type processField struct {
+	Name  string
+	Value string
+}
+
+// processDetails holds everything shown in the detail view of a process.
+// Values that can't be read are left empty.
+type processDetails struct {
+	PId int32
+	// Arguments of the command line, without truncating.
+	Args []string
+	// Environment variables, as NAME=value, and why they couldn't be read.
+	// Only the owner of the process can read them.
+	Environ    []string
+	EnvironErr error
+	Cwd        string
+	Exe        string
+	// Fields of the status file, in order, like Uid or CapEff.
+	Status []processField
+	// Lines of the cgroup file, one for each hierarchy.
+	Cgroups []string
+	// Namespaces the process is in, like net:[4026531840].
+	Namespaces []processField
+	// Lines of the limits file, including its heading.
+	Limits []string
+}
+
+// openFile is a file descriptor open by a process, as lsof lists them.
+type openFile struct {
+	// Number of the file descriptor.
+	FD int
+	// Access mode: r for reading, w for writing or u for both.
+	Mode string
+	// Kind of file, like REG, DIR, CHR, FIFO, IPv4, IPv6, unix or a_inode.
+	Type string
+	// Inode of the file, empty for anonymous ones.
+	Node string
+	// Path of the file, or the addresses of a socket with its state.
+	Name string
+}
+
+// memoryMapping is a region of the address space of a process, or the sum of
+// several of them. Sizes are in bytes.
+type memoryMapping struct {
+	// Start of the region and its range, like 7f2a1c000000-7f2a1c021000.
+	Start   uint64
+	Address string
+	// Permissions, like r-xp.
+	Perms string
+	// File mapped, or the kind of the region, like [heap], [stack] or
+	// [anon].
+	Name string
+	// Amount of regions added up, when the mappings are grouped by file.
+	Count int
+	Size  uint64
+	// Resident size and the proportional share of it, dividing each page
+	// by the amount of processes mapping it.
+	Rss uint64
+	Pss uint64
+	// Swapped out size.
+	Swap uint64
+	// Modified pages, both shared and private.
+	Dirty uint64
+}
+
+// memoryMaps are the regions of the address space of a process, with their
+// totals.
+type memoryMaps struct {
+	PId      int32
+	Total    memoryMapping
+	Mappings []memoryMapping
+}
+
+// mappingsLess compares two memory mappings by the value of a memory maps
+// table column, indexed by the column's key.
+var mappingsLess = map[string]func(a, b memoryMapping) bool{
+	"Address": func(a, b memoryMapping) bool { return a.Start < b.Start },
+	"Perms":   func(a, b memoryMapping) bool { return a.Perms < b.Perms },
+	"Name":    func(a, b memoryMapping) bool { return a.Name < b.Name },
+	"Size":    func(a, b memoryMapping) bool { return a.Size < b.Size },
+	"Rss":     func(a, b memoryMapping) bool { return a.Rss < b.Rss },
+	"Pss":     func(a, b memoryMapping) bool { return a.Pss < b.Pss },
+	"Swap":    func(a, b memoryMapping) bool { return a.Swap < b.Swap },
+	"Dirty":   func(a, b memoryMapping) bool { return a.Dirty < b.Dirty },
+}
+
+// sortMappings sorts the memory mappings in place by the given column key.
+// Ties are broken by the address.
+func sortMappings(mappings []memoryMapping, key string, desc bool) {
+	less, ok := mappingsLess[key]
+	if !ok {
+		less = mappingsLess["Address"]
+	}
+
+	sort.SliceStable(mappings, func(i, j int) bool {
+		a, b := mappings[i], mappings[j]
+		if less(a, b) {
+			return !desc
+		}
+		if less(b, a) {
+			return desc
+		}
+
+		return a.Start < b.Start
+	})
+}
+
+// groupMappings adds up the memory mappings of each file, and of each kind
+// of anonymous region. Each group starts where its first mapping does.
+func groupMappings(mappings []memoryMapping) []memoryMapping {
+	var groups []memoryMapping
+	index := make(map[string]int)
+	for _, mapping := range mappings {
+		i, ok := index[mapping.Name]
+		if !ok {
+			index[mapping.Name] = len(groups)
+			groups = append(groups, memoryMapping{Start: mapping.Start, Name: mapping.Name})
+			i = len(groups) - 1
+		}
+
+		g := &groups[i]
+		g.Start = min(g.Start, mapping.Start)
+		g.Count += mapping.Count
+		g.Size += mapping.Size
+		g.Rss += mapping.Rss
+		g.Pss += mapping.Pss
+		g.Swap += mapping.Swap
+		g.Dirty += mapping.Dirty
+	}
+
+	return groups
+}
+
+// disksLess compares two disks by the value of a disks table column, indexed
+// by the column's key.
+var disksLess = map[string]func(a, b diskInfo) bool{
+	"FsType":      func(a, b diskInfo) bool { return a.FsType < b.FsType },
+	"Device":      func(a, b diskInfo) bool { return a.Device < b.Device },
+	"MountPath":   func(a, b diskInfo) bool { return a.MountPath < b.MountPath },
+	"TotalSize":   func(a, b diskInfo) bool { return a.TotalSize < b.TotalSize },
+	"FreeSize":    func(a, b diskInfo) bool { return a.FreeSize < b.FreeSize },
+	"UsedSize":    func(a, b diskInfo) bool { return a.UsedSize < b.UsedSize },
+	"UsedPercent": func(a, b diskInfo) bool { return a.UsedPercent < b.UsedPercent },
+	"InodesTotal": func(a, b diskInfo) bool { return a.InodesTotal < b.InodesTotal },
+	"InodesUsed":  func(a, b diskInfo) bool { return a.InodesUsed < b.InodesUsed },
+	"InodesFree":  func(a, b diskInfo) bool { return a.InodesFree < b.InodesFree },
+	"ReadRate":    func(a, b diskInfo) bool { return a.ReadRate < b.ReadRate },
+	"WriteRate":   func(a, b diskInfo) bool { return a.WriteRate < b.WriteRate },
+	"IOPS":        func(a, b diskInfo) bool { return a.IOPS < b.IOPS },
+	"Utilization": func(a, b diskInfo) bool { return a.Utilization < b.Utilization },
+	"Await":       func(a, b diskInfo) bool { return a.Await < b.Await },
+}
+
+// sortDisks sorts the disks in place by the given column key. As with the
+// processes, the table can't sort them by itself as the rates are rendered as
+// formatted strings. Ties are broken by the mount path.
+func sortDisks(disks []diskInfo, key string, desc bool) {
+	less, ok := disksLess[key]
+	if !ok {
+		less = disksLess["MountPath"]
+	}
+
+	sort.SliceStable(disks, func(i, j int) bool {
+		a, b := disks[i], disks[j]
+		if less(a, b) {
+			return !desc
+		}
+		if less(b, a) {
+			return desc
+		}
+
+		return a.MountPath < b.MountPath
+	})
+}
+
+// getCpuInfo returns the usage of the whole CPU and of each core since the
+// previous call.
+func getCpuInfo() (cpuUsage, []cpuUsage) {
+	return cpusCollector.collect()
+}
+
+// collect returns the usage of the whole CPU and of each core between the
+// previous collection and this one. On the first collection the usage is
+// averaged since the system booted.
+func (c *cpuCollector) collect() (cpuUsage, []cpuUsage) {
+	// Ignoring errors as the usage is shown empty until the times can be read.
+	all, cores, err := readCpuTimes()
+	if err != nil {
+		return cpuUsage{}, make([]cpuUsage, len(c.prevCores))
+	}
+
+	allUsage := all.usageSince(c.prevAll)
+	coresUsage := make([]cpuUsage, len(cores))
+	for i, core := range cores {
+		var prev cpuTimes
+		if i < len(c.prevCores) {
+			prev = c.prevCores[i]
+		}
+		coresUsage[i] = core.usageSince(prev)
+	}
+
+	c.prevAll = all
+	c.prevCores = cores
+
+	return allUsage, coresUsage
+}
+
+// total returns the time spent in every state.
+func (t cpuTimes) total() float64 {
+	return t.User + t.Nice + t.System + t.Idle + t.IOWait + t.Irq + t.Steal
+}
+
+// usageSince returns the percentage of time spent in each state since the
+// prev times were taken.
+func (t cpuTimes) usageSince(prev cpuTimes) cpuUsage {
+	elapsed := t.total() - prev.total()
+	// A core brought back online restarts its counters.
+	if elapsed <= 0 || t.Idle < prev.Idle {
+		return cpuUsage{}
+	}
+
+	percent := func(now, before float64) float64 {
+		return max(now-before, 0) / elapsed * 100
+	}
+
+	return cpuUsage{
+		User:   percent(t.User, prev.User),
+		Nice:   percent(t.Nice, prev.Nice),
+		System: percent(t.System, prev.System),
+		IOWait: percent(t.IOWait, prev.IOWait),
+		Irq:    percent(t.Irq, prev.Irq),
+		Steal:  percent(t.Steal, prev.Steal),
+	}
 }
 
-// getCpuInfo returns the information of the cores in the system.
-func getCpuInfo() []float64 {
-	cpuInfo, _ := cpu.Percent(0, true)
-	return cpuInfo
+// Total returns the percentage of time the CPU wasn't idle.
+func (u cpuUsage) Total() float64 {
+	return u.User + u.Nice + u.System + u.IOWait + u.Irq + u.Steal
 }
 
 // getMemoryInfo returns virtual and swap memory.
@@ -66,28 +523,108 @@ func getMemoryInfo() (memoryInfo, memoryInfo) {
 	sm, _ := mem.SwapMemory()
 
 	vMemoryInfo := memoryInfo{
-		Total:       float64(vm.Total) / GB,
-		Used:        float64(vm.Used) / GB,
+		Total:       vm.Total,
+		Used:        vm.Used,
 		UsedPercent: vm.UsedPercent,
+		Buffers:     vm.Buffers,
+		Cached:      vm.Cached,
+		Shared:      vm.Shared,
+		Available:   vm.Available,
 	}
 
 	sMemoryInfo := memoryInfo{
-		Total:       float64(sm.Total) / GB,
-		Used:        float64(sm.Used) / GB,
+		Total:       sm.Total,
+		Used:        sm.Used,
 		UsedPercent: sm.UsedPercent,
 	}
 
 	return vMemoryInfo, sMemoryInfo
 }
 
-// getDiskInfo returns an array of the information of relevant disks in the
-// system.
-func getDiskInfo() []diskInfo {
+// newDiskFilter returns a filter showing the file systems of the given comma
+// separated types, or of every type when types is "all", except the ones of
+// the excluded types. The device and mount path patterns are regular
+// expressions, ignored when empty.
+func newDiskFilter(types, excludeTypes, device, excludeDevice, mountPath, excludeMountPath string) (diskFilter, error) {
+	var filter diskFilter
+
+	if strings.TrimSpace(types) != "all" {
+		filter.types = splitSet(types)
+	}
+	filter.excludeTypes = splitSet(excludeTypes)
+
+	patterns := []struct {
+		name    string
+		pattern string
+		re      **regexp.Regexp
+	}{
+		{"device", device, &filter.device},
+		{"excluded device", excludeDevice, &filter.excludeDevice},
+		{"mount path", mountPath, &filter.mountPath},
+		{"excluded mount path", excludeMountPath, &filter.excludeMountPath},
+	}
+	for _, p := range patterns {
+		if p.pattern == "" {
+			continue
+		}
+
+		re, err := regexp.Compile(p.pattern)
+		if err != nil {
+			return diskFilter{}, fmt.Errorf("invalid %s pattern %q: %v", p.name, p.pattern, err)
+		}
+		*p.re = re
+	}
+
+	return filter, nil
+}
+
+// splitSet returns the non empty values of a comma separated list.
+func splitSet(list string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			set[value] = struct{}{}
+		}
+	}
+
+	return set
+}
+
+// match reports whether the file system is shown.
+func (f diskFilter) match(p disk.PartitionStat) bool {
+	if _, ok := f.types[p.Fstype]; f.types != nil && !ok {
+		return false
+	}
+	if _, ok := f.excludeTypes[p.Fstype]; ok {
+		return false
+	}
+
+	if f.device != nil && !f.device.MatchString(p.Device) {
+		return false
+	}
+	if f.excludeDevice != nil && f.excludeDevice.MatchString(p.Device) {
+		return false
+	}
+	if f.mountPath != nil && !f.mountPath.MatchString(p.Mountpoint) {
+		return false
+	}
+	if f.excludeMountPath != nil && f.excludeMountPath.MatchString(p.Mountpoint) {
+		return false
+	}
+
+	return true
+}
+
+// getDiskInfo returns an array of the information of the disks in the
+// system shown by the filter.
+func getDiskInfo(filter diskFilter) []diskInfo {
 	var disks []diskInfo
 
+	ios := disksIOCollector.collect()
+
 	dps, _ := disk.Partitions(true)
 	for _, dsk := range dps {
-		if _, ok := fsFilter[dsk.Fstype]; !ok {
+		if !filter.match(dsk) {
 			continue
 		}
 
@@ -98,9 +635,16 @@ func getDiskInfo() []diskInfo {
 			FsType:    dsk.Fstype,
 			Device:    dsk.Device,
 			MountPath: mount,
-			TotalSize: float64(dskUsg.Total) / GB,
-			FreeSize:  float64(dskUsg.Free) / GB,
-			UsedSize:  float64(dskUsg.Used) / GB,
+			TotalSize: dskUsg.Total,
+			FreeSize:  dskUsg.Free,
+			UsedSize:  dskUsg.Used,
+
+			UsedPercent: dskUsg.UsedPercent,
+			InodesTotal: dskUsg.InodesTotal,
+			InodesUsed:  dskUsg.InodesUsed,
+			InodesFree:  dskUsg.InodesFree,
+
+			diskIO: ios[blockDeviceName(dsk.Device)],
 		}
 
 		disks = append(disks, diskInfo)
@@ -108,3 +652,112 @@ func getDiskInfo() []diskInfo {
 
 	return disks
 }
+
+// collect returns the activity of each block device since the previous
+// collection, indexed by the device's name. On Linux the counters come from
+// /proc/diskstats.
+func (c *diskIOCollector) collect() map[string]diskIO {
+	// Ignoring errors as the activity is shown empty until the counters can
+	// be read.
+	counters, _ := disk.IOCounters()
+	now := time.Now()
+	elapsed := now.Sub(c.prevTime).Seconds()
+
+	ios := make(map[string]diskIO, len(counters))
+	for name, counter := range counters {
+		prev, ok := c.prev[name]
+		if !ok || elapsed <= 0 || diskCountersReset(counter, prev) {
+			continue
+		}
+
+		operations := float64(counter.ReadCount - prev.ReadCount + counter.WriteCount - prev.WriteCount)
+		io := diskIO{
+			ReadRate:    float64(counter.ReadBytes-prev.ReadBytes) / elapsed,
+			WriteRate:   float64(counter.WriteBytes-prev.WriteBytes) / elapsed,
+			IOPS:        operations / elapsed,
+			Utilization: min(float64(counter.IoTime-prev.IoTime)/(elapsed*1000)*100, 100),
+		}
+		if operations > 0 {
+			io.Await = float64(counter.ReadTime-prev.ReadTime+counter.WriteTime-prev.WriteTime) / operations
+		}
+
+		ios[name] = io
+	}
+
+	c.prev = counters
+	c.prevTime = now
+
+	return ios
+}
+
+// blockDeviceName returns the name the kernel gives to the device at the
+// given path, resolving links such as the ones in /dev/mapper.
+func blockDeviceName(device string) string {
+	if resolved, err := filepath.EvalSymlinks(device); err == nil {
+		device = resolved
+	}
+
+	return filepath.Base(device)
+}
+
+// getNetworkInfo returns the traffic of each network interface since the
+// previous call.
+func getNetworkInfo() []networkInfo {
+	return networksCollector.collect()
+}
+
+// collect returns the traffic of each network interface, per second, since
+// the previous collection. Interfaces seen for the first time show no
+// traffic.
+func (c *networkCollector) collect() []networkInfo {
+	// Ignoring errors as no interface is shown until the counters can be
+	// read.
+	counters, _ := net.IOCounters(true)
+	now := time.Now()
+	elapsed := now.Sub(c.prevTime).Seconds()
+
+	// Counters going backwards were reset, as when the interface is
+	// recreated.
+	rate := func(current, previous uint64) float64 {
+		if current < previous || elapsed <= 0 {
+			return 0
+		}
+
+		return float64(current-previous) / elapsed
+	}
+
+	var networks []networkInfo
+	current := make(map[string]net.IOCountersStat, len(counters))
+	for _, counter := range counters {
+		current[counter.Name] = counter
+		network := networkInfo{Interface: counter.Name}
+
+		if prev, ok := c.prev[counter.Name]; ok {
+			network.RecvRate = rate(counter.BytesRecv, prev.BytesRecv)
+			network.SentRate = rate(counter.BytesSent, prev.BytesSent)
+			network.PacketsRecv = rate(counter.PacketsRecv, prev.PacketsRecv)
+			network.PacketsSent = rate(counter.PacketsSent, prev.PacketsSent)
+			network.Errors = rate(counter.Errin+counter.Errout, prev.Errin+prev.Errout)
+			network.Drops = rate(counter.Dropin+counter.Dropout, prev.Dropin+prev.Dropout)
+		}
+
+		networks = append(networks, network)
+	}
+
+	c.prev = current
+	c.prevTime = now
+
+	return networks
+}
+
+// isVirtualInterface reports whether the network interface is the loopback
+// or a virtual one.
+func isVirtualInterface(name string) bool {
+	for _, prefix := range virtualInterfacePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
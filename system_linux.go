@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cpuSysRoot is the sysfs directory holding a subdirectory for each core.
+var cpuSysRoot = "/sys/devices/system/cpu"
+
+// getSystemInfo returns the load average, the uptime, the frequency of each
+// core and the pressure stall information. Values that can't be read are left
+// empty.
+func getSystemInfo() systemInfo {
+	var info systemInfo
+
+	if load, err := processesCollector.readLoadAverage(); err == nil {
+		info.Load1, info.Load5, info.Load15 = load[0], load[1], load[2]
+	}
+
+	if uptime, err := processesCollector.readUptime(); err == nil {
+		info.Uptime = time.Duration(uptime * float64(time.Second))
+	}
+
+	info.Frequencies = readCpuFrequencies(cpuSysRoot)
+
+	// Ignoring errors as the header notices the pressure is missing.
+	info.Pressure, _ = processesCollector.readPressure()
+
+	return info
+}
+
+// readLoadAverage returns the load averages over 1, 5 and 15 minutes from the
+// loadavg file in the procfs root.
+func (c *procCollector) readLoadAverage() ([3]float64, error) {
+	var load [3]float64
+
+	data, err := os.ReadFile(filepath.Join(c.root, "loadavg"))
+	if err != nil {
+		return load, err
+	}
+
+	// 1min 5min 15min running/total lastpid
+	fields := strings.Fields(string(data))
+	if len(fields) < 3 {
+		return load, fmt.Errorf("malformed loadavg file in %s", c.root)
+	}
+
+	for i := range load {
+		if load[i], err = strconv.ParseFloat(fields[i], 64); err != nil {
+			return load, err
+		}
+	}
+
+	return load, nil
+}
+
+// pressureResources are the resources the kernel reports the pressure stall
+// information of, in the order they are shown.
+var pressureResources = []string{"cpu", "memory", "io"}
+
+// readPressure returns the pressure stall information of each resource from
+// the pressure directory in the procfs root. It only exists on kernels built
+// with PSI support, since 4.20.
+func (c *procCollector) readPressure() ([]pressure, error) {
+	var pressures []pressure
+	for _, resource := range pressureResources {
+		data, err := os.ReadFile(filepath.Join(c.root, "pressure", resource))
+		if err != nil {
+			return nil, err
+		}
+
+		p := pressure{Resource: resource}
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			// some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+			fields := strings.Fields(line)
+			if len(fields) < 4 {
+				return nil, fmt.Errorf("malformed %s pressure file in %s", resource, c.root)
+			}
+
+			var stall pressureStall
+			for i, avg := range []*float64{&stall.Avg10, &stall.Avg60, &stall.Avg300} {
+				_, value, _ := strings.Cut(fields[i+1], "=")
+				if *avg, err = strconv.ParseFloat(value, 64); err != nil {
+					return nil, err
+				}
+			}
+
+			switch fields[0] {
+			case "some":
+				p.Some = stall
+			case "full":
+				p.Full, p.HasFull = stall, true
+			}
+		}
+		pressures = append(pressures, p)
+	}
+
+	return pressures, nil
+}
+
+// readCpuFrequencies returns the current frequency of each core under root,
+// ordered by the core's index. Cores without a cpufreq directory, as when
+// they are offline, are skipped, so each frequency keeps the index of its
+// cpuN directory. Virtual machines usually don't expose it, in which case
+// nothing is returned.
+func readCpuFrequencies(root string) []cpuFrequency {
+	paths, _ := filepath.Glob(filepath.Join(root, "cpu[0-9]*", "cpufreq", "scaling_cur_freq"))
+
+	var frequencies []cpuFrequency
+	for _, path := range paths {
+		core, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(filepath.Dir(filepath.Dir(path))), "cpu"))
+		if err != nil {
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		kHz, err := strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+		if err != nil {
+			continue
+		}
+		frequencies = append(frequencies, cpuFrequency{Core: core, MHz: kHz / 1000})
+	}
+
+	// cpu10 has to go after cpu9.
+	sort.Slice(frequencies, func(i, j int) bool { return frequencies[i].Core < frequencies[j].Core })
+
+	return frequencies
+}
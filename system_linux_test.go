@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// fixtureCpuSys is a sysfs directory of the cores where the core 1 is offline,
+// so it has no cpufreq directory.
+const fixtureCpuSys = "testdata/sys/devices/system/cpu"
+
+func TestReadCpuFrequencies(t *testing.T) {
+	want := []cpuFrequency{{Core: 0, MHz: 2400}, {Core: 2, MHz: 1800.5}, {Core: 10, MHz: 3100}}
+	if got := readCpuFrequencies(fixtureCpuSys); !reflect.DeepEqual(got, want) {
+		t.Errorf("readCpuFrequencies = %+v, want %+v", got, want)
+	}
+
+	if got := readCpuFrequencies("testdata/missing"); got != nil {
+		t.Errorf("readCpuFrequencies without cpufreq = %+v, want nothing", got)
+	}
+}
+
+func TestProcCollectorReadPressure(t *testing.T) {
+	pressures, err := newProcCollector(fixtureProc).readPressure()
+	if err != nil {
+		t.Fatalf("readPressure: %v", err)
+	}
+
+	want := []pressure{
+		// Kernels older than 5.13 have no full line for the CPU.
+		{Resource: "cpu", Some: pressureStall{Avg10: 1.5, Avg60: 0.75, Avg300: 0.25}},
+		{Resource: "memory", Some: pressureStall{Avg10: 10, Avg60: 5, Avg300: 2.5},
+			Full: pressureStall{Avg10: 4, Avg60: 2, Avg300: 1}, HasFull: true},
+		{Resource: "io", HasFull: true},
+	}
+	if !reflect.DeepEqual(pressures, want) {
+		t.Errorf("readPressure =\n%+v\nwant\n%+v", pressures, want)
+	}
+}
+
+func TestProcCollectorReadPressureErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cpu  string
+	}{
+		// Kernels without PSI support have no pressure directory.
+		{"missing", ""},
+		{"malformed", "some avg10=1.50\n"},
+		{"not a number", "some avg10=high avg60=0.75 avg300=0.25 total=1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			if tt.cpu != "" {
+				dir := filepath.Join(root, "pressure")
+				if err := os.Mkdir(dir, 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(dir, "cpu"), []byte(tt.cpu), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if _, err := newProcCollector(root).readPressure(); err == nil {
+				t.Error("readPressure succeeded, want an error")
+			}
+		})
+	}
+}
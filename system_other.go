@@ -0,0 +1,37 @@
+//go:build !linux
+
+package main
+
+import (
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/host"
+	"github.com/shirou/gopsutil/v3/load"
+)
+
+// getSystemInfo returns the load average, the uptime and the frequency of
+// each core. Values that can't be read are left empty, like the pressure
+// stall information which only Linux reports.
+func getSystemInfo() systemInfo {
+	var info systemInfo
+
+	if avg, err := load.Avg(); err == nil {
+		info.Load1, info.Load5, info.Load15 = avg.Load1, avg.Load5, avg.Load15
+	}
+
+	if uptime, err := host.Uptime(); err == nil {
+		info.Uptime = time.Duration(uptime) * time.Second
+	}
+
+	// Only the nominal frequency is reported, the same for every core.
+	if cpus, err := cpu.Info(); err == nil {
+		for _, c := range cpus {
+			for i := int32(0); i < c.Cores; i++ {
+				info.Frequencies = append(info.Frequencies, cpuFrequency{Core: len(info.Frequencies), MHz: c.Mhz})
+			}
+		}
+	}
+
+	return info
+}
@@ -0,0 +1,129 @@
+// File that describes the table of the usage and limits of each cgroup.
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/evertras/bubble-table/table"
+)
+
+const (
+	// Width of the bars of the cgroups table, including the percentage.
+	cgroupBarWidth = 14
+	// Percentage of a limit from which the usage is highlighted as close to
+	// it.
+	nearLimitPercent = 90
+)
+
+// newCgroupsTable instantiates the cgroups table with its assigned columns,
+// filling the height left below the header. This is called only when the
+// application starts or it resizes.
+func newCgroupsTable(m model) table.Model {
+	bar := func(key, title string) table.Column {
+		return table.NewColumn(key, title, cgroupBarWidth)
+	}
+
+	columns := []table.Column{
+		table.NewFlexColumn("Path", "Cgroup", columnLargestFlexFactor),
+		table.NewFlexColumn("Cpu", "CPU / limit", columnLargerFlexFactor),
+		bar("CpuBar", "CPU of limit"),
+		table.NewFlexColumn("Memory", "Memory / limit", columnLargerFlexFactor),
+		bar("MemoryBar", "Mem of limit"),
+		table.NewFlexColumn("IOReadRate", "IO_R", columnDefaultFlexFactor),
+		table.NewFlexColumn("IOWriteRate", "IO_W", columnDefaultFlexFactor),
+		table.NewFlexColumn("Pids", "Tasks / limit", columnDefaultFlexFactor),
+		bar("PidsBar", "Tasks of limit"),
+	}
+
+	// Lines taken by the header, the help and the borders, title and footer
+	// of the table.
+	height := m.Height - 7
+	if m.Height >= minimumHeightTwoTables {
+		height -= headerTableHeight
+	}
+
+	return table.
+		New(columns).
+		BorderRounded().
+		WithBaseStyle(styleBase.Copy().Align(lipgloss.Left)).
+		WithTargetWidth(m.Width).
+		WithPageSize(max(height, 1)).
+		WithKeyMap(processesTableKeyMap()).
+		WithRows(generateCgroupsTableRows(m)).
+		Focused(true)
+}
+
+// generateCgroupsTableRows will generate all the rows that will be rendered
+// into the cgroups table. The bars are relative to the limit of each cgroup,
+// and left out for the cgroups without one. This is called each time the
+// application updates while the table is shown.
+func generateCgroupsTableRows(m model) []table.Row {
+	var rows []table.Row
+
+	units := m.opts.units
+	for _, cgroup := range m.Cgroups {
+		rowData := make(table.RowData)
+
+		rowData["Path"] = cgroup.Path
+		rowData["Cpu"] = fmt.Sprintf("%.1f%%", cgroup.CpuPercentage)
+		if cgroup.CpuLimit > 0 {
+			rowData["Cpu"] = fmt.Sprintf("%.1f%% / %.0f%%", cgroup.CpuPercentage, cgroup.CpuLimit)
+		}
+		rowData["CpuBar"] = limitBar(cgroup.CpuPercentage, cgroup.CpuLimit)
+
+		rowData["Memory"] = units.format(cgroup.MemoryCurrent)
+		if cgroup.MemoryMax > 0 {
+			rowData["Memory"] = units.format(cgroup.MemoryCurrent) + " / " + units.format(cgroup.MemoryMax)
+		}
+		rowData["MemoryBar"] = limitBar(float64(cgroup.MemoryCurrent), float64(cgroup.MemoryMax))
+
+		rowData["IOReadRate"] = units.formatRate(cgroup.IOReadRate)
+		rowData["IOWriteRate"] = units.formatRate(cgroup.IOWriteRate)
+
+		rowData["Pids"] = fmt.Sprint(cgroup.PidsCurrent)
+		if cgroup.PidsMax > 0 {
+			rowData["Pids"] = fmt.Sprintf("%d / %d", cgroup.PidsCurrent, cgroup.PidsMax)
+		}
+		rowData["PidsBar"] = limitBar(float64(cgroup.PidsCurrent), float64(cgroup.PidsMax))
+
+		row := table.NewRow(rowData).WithStyle(standardRowStyle)
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// limitBar renders the usage relative to its limit as a bar, highlighted when
+// the usage is close to the limit. Nothing is rendered without a limit.
+func limitBar(usage, limit float64) any {
+	if limit <= 0 {
+		return ""
+	}
+
+	percent := usage / limit * 100
+	bar := usageBar(percent, cgroupBarWidth)
+	if percent >= nearLimitPercent {
+		return table.NewStyledCell(bar, alertStyle)
+	}
+
+	return bar
+}
+
+// updateCgroups collects the usage and limits of each cgroup again.
+func (m model) updateCgroups() model {
+	m.Cgroups, m.cgroupsErr = m.cgroupCollector.collect()
+	m.cgroupsTable = m.cgroupsTable.WithRows(generateCgroupsTableRows(m))
+
+	return m
+}
+
+// cgroupsView renders the cgroups table, or why it can't be shown.
+func (m model) cgroupsView() string {
+	if m.cgroupsErr != nil {
+		return lipgloss.NewStyle().Padding(1).Render(
+			standardRowStyle.Render(fmt.Sprintf("The cgroups can't be shown: %v. Another hierarchy can be given with -cgroup-root.", m.cgroupsErr)))
+	}
+
+	return lipgloss.NewStyle().Padding(0, 1).Render(m.cgroupsTable.View())
+}
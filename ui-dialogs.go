@@ -0,0 +1,734 @@
+// File that describes the dialogs shown when the user acts on the processes.
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// dialogKind identifies the dialog being shown in place of the processes
+// table.
+type dialogKind int
+
+const (
+	dialogNone dialogKind = iota
+	// Lists the signals that can be sent to the processes.
+	dialogSignal
+	// Asks the user to confirm sending the chosen signal.
+	dialogSignalConfirm
+	// Asks the user for the nice value to set to the processes.
+	dialogNice
+	// Lists the I/O scheduling classes that can be set to the processes.
+	dialogIOClass
+	// Lists the levels of the chosen I/O scheduling class.
+	dialogIOLevel
+	// Asks the user for the query the processes are searched by.
+	dialogSearch
+	// Asks the user for the query the processes are filtered by.
+	dialogFilter
+	// Lists the columns the processes can be sorted by.
+	dialogSort
+	// Asks the user for the text the open files are filtered by.
+	dialogFilesFilter
+)
+
+// inline reports whether the dialog is shown below the processes or the open
+// files table instead of in its place, as the user needs to see the table
+// while typing.
+func (d dialogKind) inline() bool {
+	return d == dialogSearch || d == dialogFilter || d == dialogFilesFilter
+}
+
+const (
+	// Range of the nice values, from the highest priority to the lowest.
+	minNice = -20
+	maxNice = 19
+	// Amount of levels of the realtime and best effort I/O classes.
+	ioPriorityLevels = 8
+)
+
+// ioClassOption is an I/O scheduling class the user can set to the processes.
+type ioClassOption struct {
+	name  string
+	class int
+	// Whether the class takes a level, otherwise it is always 0.
+	leveled bool
+}
+
+// ioClassOptions are the classes listed in the I/O priority menu, the first
+// one being the default choice.
+var ioClassOptions = []ioClassOption{
+	{"Best effort", ioPriorityClassBestEffort, true},
+	{"Realtime", ioPriorityClassRealtime, true},
+	{"Idle", ioPriorityClassIdle, false},
+	{"None (follows the nice value)", ioPriorityClassNone, false},
+}
+
+var (
+	dialogStyle = (lipgloss.
+			NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("#92DCE5")).
+			Padding(0, 1))
+
+	dialogTitleStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#92DCE5")).Bold(true)
+	menuCursorStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#1B1B1E")).Background(lipgloss.Color("#92DCE5"))
+)
+
+// menu is a list of options the user picks one from with the arrow keys.
+type menu struct {
+	title   string
+	options []string
+	cursor  int
+}
+
+// update moves the menu's cursor given the key pressed.
+func (mn menu) update(msg tea.KeyMsg) menu {
+	switch msg.String() {
+	case "up", "k":
+		if mn.cursor > 0 {
+			mn.cursor--
+		}
+	case "down", "j":
+		if mn.cursor < len(mn.options)-1 {
+			mn.cursor++
+		}
+	case "home", "g":
+		mn.cursor = 0
+	case "end", "G":
+		mn.cursor = len(mn.options) - 1
+	}
+
+	return mn
+}
+
+// view renders the menu's title and options, highlighting the one under the
+// cursor.
+func (mn menu) view() string {
+	lines := []string{dialogTitleStyle.Render(mn.title), ""}
+	for i, option := range mn.options {
+		if i == mn.cursor {
+			lines = append(lines, menuCursorStyle.Render(" "+option+" "))
+		} else {
+			lines = append(lines, standardRowStyle.Render(" "+option+" "))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// processActionsShown reports whether the dialogs acting on the processes
+// are drawn, along with the processes they act on: the processes table or
+// the detail view. The keys acting on the processes are ignored otherwise,
+// so they never act on a process the user can't see.
+func (m model) processActionsShown() bool {
+	return m.screen == screenProcess || m.processesTableShown()
+}
+
+// processesTableShown reports whether the processes table is drawn, which
+// only happens on the tables screen when the window is tall enough for every
+// table. The keys changing how the table is shown are ignored otherwise, as
+// is the prompt of the search and the filter drawn below it.
+func (m model) processesTableShown() bool {
+	return m.screen == screenTables && m.tablesHeight() >= minimumHeightAllTables
+}
+
+// targetProcesses returns the IDs of the processes an action applies to: the
+// one of the detail view while it is shown, otherwise the tagged ones or,
+// when none is tagged, the highlighted one.
+func (m model) targetProcesses() []int32 {
+	if m.screen == screenProcess {
+		return []int32{m.detailPId}
+	}
+
+	if len(m.tagged) > 0 {
+		var pIds []int32
+		for _, process := range m.Processes {
+			if _, ok := m.tagged[process.PId]; ok {
+				pIds = append(pIds, process.PId)
+			}
+		}
+
+		return pIds
+	}
+
+	if len(m.processesTable.GetVisibleRows()) == 0 {
+		return nil
+	}
+
+	if pId, ok := m.processesTable.HighlightedRow().Data["PId"].(int32); ok {
+		return []int32{pId}
+	}
+
+	return nil
+}
+
+// toggleTag tags or untags the highlighted process and moves the highlight to
+// the next one.
+func (m model) toggleTag() model {
+	if len(m.processesTable.GetVisibleRows()) == 0 {
+		return m
+	}
+
+	pId, ok := m.processesTable.HighlightedRow().Data["PId"].(int32)
+	if !ok {
+		return m
+	}
+
+	if _, ok := m.tagged[pId]; ok {
+		delete(m.tagged, pId)
+	} else {
+		m.tagged[pId] = struct{}{}
+	}
+
+	m.processesTable = m.processesTable.
+		WithRows(generateProcessesTableRows(m)).
+		WithHighlightedRow(m.processesTable.GetHighlightedRowIndex() + 1)
+
+	return m
+}
+
+// openSignalMenu shows the signal menu for the targeted processes.
+func (m model) openSignalMenu() model {
+	m.dialogTargets = m.targetProcesses()
+	if len(m.dialogTargets) == 0 {
+		return m
+	}
+
+	var options []string
+	for _, sig := range signalOptions {
+		options = append(options, fmt.Sprintf("%2d %s", sig.signal, sig.name))
+	}
+
+	m.signalMenu = menu{
+		title:   "Send signal:",
+		options: options,
+	}
+	m.dialog = dialogSignal
+
+	return m
+}
+
+// renice adds delta to the nice value of each targeted process. Used by the
+// F7 and F8 keys, the values are clamped to the range the kernel accepts.
+func (m model) renice(delta int) model {
+	targets := m.targetProcesses()
+	if len(targets) == 0 {
+		return m
+	}
+
+	nices := make(map[int32]int, len(targets))
+	for _, process := range m.Processes {
+		nices[process.PId] = int(process.Nice)
+	}
+
+	var failed []string
+	for _, pId := range targets {
+		nice := nices[pId] + delta
+		if nice < minNice {
+			nice = minNice
+		} else if nice > maxNice {
+			nice = maxNice
+		}
+
+		if err := setNice(pId, nice); err != nil {
+			failed = append(failed, fmt.Sprintf("PID %d: %v", pId, err))
+			continue
+		}
+		m = m.updateNice(pId, nice)
+	}
+
+	if len(failed) > 0 {
+		m.status = "Failed to change the nice value of " + strings.Join(failed, ", ")
+		m.statusIsError = true
+	} else {
+		m.status = "Changed the nice value of " + describeTargets(targets)
+		m.statusIsError = false
+	}
+
+	m.processesTable = m.processesTable.WithRows(generateProcessesTableRows(m))
+
+	return m
+}
+
+// openNicePrompt asks the user for the nice value to set to the targeted
+// processes.
+func (m model) openNicePrompt() (model, tea.Cmd) {
+	m.dialogTargets = m.targetProcesses()
+	if len(m.dialogTargets) == 0 {
+		return m, nil
+	}
+
+	m.niceInput = textinput.New()
+	m.niceInput.Placeholder = fmt.Sprintf("%d to %d", minNice, maxNice)
+	m.niceInput.CharLimit = 3
+	m.niceInput.Width = 10
+	m.dialog = dialogNice
+
+	return m, m.niceInput.Focus()
+}
+
+// openIOPriorityMenu shows the I/O scheduling classes for the targeted
+// processes.
+func (m model) openIOPriorityMenu() model {
+	m.dialogTargets = m.targetProcesses()
+	if len(m.dialogTargets) == 0 {
+		return m
+	}
+
+	var options []string
+	for _, class := range ioClassOptions {
+		options = append(options, class.name)
+	}
+
+	m.ioClassMenu = menu{
+		title:   "I/O scheduling class:",
+		options: options,
+	}
+	m.dialog = dialogIOClass
+
+	return m
+}
+
+// openIOLevelMenu shows the levels of the chosen I/O scheduling class.
+func (m model) openIOLevelMenu() model {
+	class := ioClassOptions[m.ioClassMenu.cursor]
+
+	var options []string
+	for level := 0; level < ioPriorityLevels; level++ {
+		switch level {
+		case 0:
+			options = append(options, fmt.Sprintf("%d (highest)", level))
+		case ioPriorityLevels - 1:
+			options = append(options, fmt.Sprintf("%d (lowest)", level))
+		default:
+			options = append(options, strconv.Itoa(level))
+		}
+	}
+
+	// Level 4 is the one the kernel uses for nice 0.
+	m.ioLevelMenu = menu{
+		title:   class.name + " level:",
+		options: options,
+		cursor:  4,
+	}
+	m.dialog = dialogIOLevel
+
+	return m
+}
+
+// darwinMissingColumns are the processes table columns Darwin's ps doesn't
+// report.
+var darwinMissingColumns = map[string]bool{
+	"Threads":     true,
+	"Shared":      true,
+	"ExeP":        true,
+	"IOReadRate":  true,
+	"IOWriteRate": true,
+	"Container":   true,
+}
+
+// openSortMenu shows the columns the processes can be sorted by, with the
+// current one under the cursor.
+func (m model) openSortMenu() model {
+	m.sortMenu = menu{title: "Sort by:"}
+	m.sortMenuKeys = nil
+	for _, option := range processesSortOptions {
+		if runtime.GOOS == "darwin" && darwinMissingColumns[option.key] {
+			continue
+		}
+
+		if option.key == m.sortKey {
+			m.sortMenu.cursor = len(m.sortMenu.options)
+		}
+		m.sortMenu.options = append(m.sortMenu.options, option.name)
+		m.sortMenuKeys = append(m.sortMenuKeys, option.key)
+	}
+	m.dialog = dialogSort
+
+	return m
+}
+
+// sortProcessesBy sorts the processes table by the column with the given
+// key and order.
+func (m model) sortProcessesBy(key string, desc bool) model {
+	m.sortKey = key
+	m.sortDesc = desc
+	m.processesTable = m.processesTable.
+		WithColumns(processesTableColumns(m)).
+		WithRows(generateProcessesTableRows(m))
+
+	return m
+}
+
+// cycleDisksSort sorts the disks table by its next column, in ascending
+// order.
+func (m model) cycleDisksSort() model {
+	next := 0
+	for i, key := range disksSortKeys {
+		if key == m.disksSortKey {
+			next = (i + 1) % len(disksSortKeys)
+		}
+	}
+
+	m.disksSortKey = disksSortKeys[next]
+	m.disksSortDesc = false
+	m.disksTable = sortDisksTable(m.disksTable, m)
+
+	return m
+}
+
+// openQueryPrompt asks the user for a search or a filter query, starting with
+// the given one.
+func (m model) openQueryPrompt(kind dialogKind, query string) (model, tea.Cmd) {
+	m.queryInput = textinput.New()
+	m.queryInput.Prompt = ""
+	m.queryInput.Placeholder = "text, re:expression, user:name or cpu>10"
+	m.queryInput.Width = m.Width / 2
+	m.queryInput.SetValue(query)
+	m.searchOrigin = m.processesTable.GetHighlightedRowIndex()
+	m.dialog = kind
+
+	return m, m.queryInput.Focus()
+}
+
+// findMatch highlights the first process matching the search query, starting
+// from the row at index from and moving step rows at a time, wrapping around
+// the table.
+func (m model) findMatch(from, step int) model {
+	rows := m.processesTable.GetVisibleRows()
+	if !m.search.active() || len(rows) == 0 {
+		return m
+	}
+
+	processes := make(map[int32]processInfo, len(m.Processes))
+	for _, process := range m.Processes {
+		processes[process.PId] = process
+	}
+
+	for n := 0; n < len(rows); n++ {
+		i := ((from+step*n)%len(rows) + len(rows)) % len(rows)
+		pId, ok := rows[i].Data["PId"].(int32)
+		if !ok {
+			continue
+		}
+
+		if m.search.match(processes[pId]) {
+			m.processesTable = m.processesTable.WithHighlightedRow(i)
+			m.status = ""
+
+			return m
+		}
+	}
+
+	m.status = fmt.Sprintf("No process matches %q", m.search.query)
+	m.statusIsError = true
+
+	return m
+}
+
+// updateDialog handles the keys pressed while a dialog is shown.
+func (m model) updateDialog(msg tea.KeyMsg) (model, tea.Cmd) {
+	k := msg.String()
+	if k == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	switch m.dialog {
+	case dialogSignal:
+		switch k {
+		case "esc", "q":
+			m.dialog = dialogNone
+		case "enter":
+			m.dialog = dialogSignalConfirm
+		default:
+			m.signalMenu = m.signalMenu.update(msg)
+		}
+	case dialogSignalConfirm:
+		switch k {
+		case "y", "Y", "enter":
+			m = m.sendSignal(signalOptions[m.signalMenu.cursor])
+			m.dialog = dialogNone
+		case "n", "N", "esc", "q":
+			m.dialog = dialogNone
+		}
+	case dialogNice:
+		switch k {
+		case "esc":
+			m.dialog = dialogNone
+		case "enter":
+			nice, err := strconv.Atoi(strings.TrimSpace(m.niceInput.Value()))
+			if err != nil || nice < minNice || nice > maxNice {
+				m.status = fmt.Sprintf("The nice value must be a number from %d to %d", minNice, maxNice)
+				m.statusIsError = true
+			} else {
+				m = m.setNice(nice)
+			}
+			m.dialog = dialogNone
+		default:
+			var cmd tea.Cmd
+			m.niceInput, cmd = m.niceInput.Update(msg)
+			return m, cmd
+		}
+	case dialogIOClass:
+		switch k {
+		case "esc", "q":
+			m.dialog = dialogNone
+		case "enter":
+			if class := ioClassOptions[m.ioClassMenu.cursor]; class.leveled {
+				m = m.openIOLevelMenu()
+			} else {
+				m = m.setIOPriority(class, 0)
+				m.dialog = dialogNone
+			}
+		default:
+			m.ioClassMenu = m.ioClassMenu.update(msg)
+		}
+	case dialogIOLevel:
+		switch k {
+		case "esc", "q":
+			m.dialog = dialogIOClass
+		case "enter":
+			m = m.setIOPriority(ioClassOptions[m.ioClassMenu.cursor], m.ioLevelMenu.cursor)
+			m.dialog = dialogNone
+		default:
+			m.ioLevelMenu = m.ioLevelMenu.update(msg)
+		}
+	case dialogSort:
+		switch k {
+		case "esc", "q":
+			m.dialog = dialogNone
+		case "enter":
+			m = m.sortProcessesBy(m.sortMenuKeys[m.sortMenu.cursor], m.sortDesc)
+			m.dialog = dialogNone
+		default:
+			m.sortMenu = m.sortMenu.update(msg)
+		}
+	case dialogSearch:
+		switch k {
+		case "esc", "enter":
+			m.dialog = dialogNone
+		case "f3", "down":
+			m = m.findMatch(m.processesTable.GetHighlightedRowIndex()+1, 1)
+		case "up":
+			m = m.findMatch(m.processesTable.GetHighlightedRowIndex()-1, -1)
+		default:
+			var cmd tea.Cmd
+			m.queryInput, cmd = m.queryInput.Update(msg)
+			// Incomplete queries, like an unclosed regular expression, are
+			// searched once they become valid.
+			if search, err := parseProcessFilter(m.queryInput.Value()); err == nil {
+				m.search = search
+				m = m.findMatch(m.searchOrigin, 1)
+			}
+
+			return m, cmd
+		}
+	case dialogFilter:
+		switch k {
+		case "esc":
+			m.dialog = dialogNone
+		case "enter":
+			filter, err := parseProcessFilter(m.queryInput.Value())
+			if err != nil {
+				m.status = "Invalid filter: " + err.Error()
+				m.statusIsError = true
+			} else {
+				m.filter = filter
+				m.status = ""
+				m.processesTable = m.processesTable.
+					WithRows(generateProcessesTableRows(m)).
+					WithHighlightedRow(0)
+			}
+			m.dialog = dialogNone
+		default:
+			var cmd tea.Cmd
+			m.queryInput, cmd = m.queryInput.Update(msg)
+			return m, cmd
+		}
+	case dialogFilesFilter:
+		switch k {
+		case "esc":
+			m = m.filterOpenFiles("")
+			m.dialog = dialogNone
+		case "enter":
+			m.dialog = dialogNone
+		default:
+			// The files are filtered while the text is typed.
+			var cmd tea.Cmd
+			m.queryInput, cmd = m.queryInput.Update(msg)
+			m = m.filterOpenFiles(m.queryInput.Value())
+			return m, cmd
+		}
+	}
+
+	return m, nil
+}
+
+// sendSignal sends the signal to the targeted processes and reports the
+// result in the status line. Tags are cleared afterwards.
+func (m model) sendSignal(sig signalOption) model {
+	var failed []string
+	for _, pId := range m.dialogTargets {
+		if err := sendSignal(pId, sig.signal); err != nil {
+			failed = append(failed, fmt.Sprintf("PID %d: %v", pId, err))
+		}
+	}
+
+	if len(failed) > 0 {
+		m.status = fmt.Sprintf("Failed to send %s to %s", sig.name, strings.Join(failed, ", "))
+		m.statusIsError = true
+	} else {
+		m.status = fmt.Sprintf("Sent %s to %s", sig.name, describeTargets(m.dialogTargets))
+		m.statusIsError = false
+	}
+
+	m.tagged = make(map[int32]struct{})
+
+	return m
+}
+
+// setNice sets the nice value to the targeted processes and reports the
+// result in the status line. Tags are cleared afterwards.
+func (m model) setNice(nice int) model {
+	var failed []string
+	for _, pId := range m.dialogTargets {
+		if err := setNice(pId, nice); err != nil {
+			failed = append(failed, fmt.Sprintf("PID %d: %v", pId, err))
+			continue
+		}
+		m = m.updateNice(pId, nice)
+	}
+
+	if len(failed) > 0 {
+		m.status = fmt.Sprintf("Failed to set nice %d to %s", nice, strings.Join(failed, ", "))
+		m.statusIsError = true
+	} else {
+		m.status = fmt.Sprintf("Set nice %d to %s", nice, describeTargets(m.dialogTargets))
+		m.statusIsError = false
+	}
+
+	m.tagged = make(map[int32]struct{})
+	m.processesTable = m.processesTable.WithRows(generateProcessesTableRows(m))
+
+	return m
+}
+
+// updateNice reflects a changed nice value in the process's information
+// without waiting for the next update. The priority of processes not under
+// a realtime policy moves along with the nice value.
+func (m model) updateNice(pId int32, nice int) model {
+	for i, process := range m.Processes {
+		if process.PId == pId {
+			m.Processes[i].Priority += int32(nice) - process.Nice
+			m.Processes[i].Nice = int32(nice)
+			break
+		}
+	}
+
+	return m
+}
+
+// setIOPriority sets the I/O scheduling class and level to the targeted
+// processes and reports the result in the status line. Tags are cleared
+// afterwards.
+func (m model) setIOPriority(class ioClassOption, level int) model {
+	name := class.name
+	if class.leveled {
+		name = fmt.Sprintf("%s %d", class.name, level)
+	}
+
+	var failed []string
+	for _, pId := range m.dialogTargets {
+		if err := setIOPriority(pId, class.class, level); err != nil {
+			failed = append(failed, fmt.Sprintf("PID %d: %v", pId, err))
+		}
+	}
+
+	if len(failed) > 0 {
+		m.status = fmt.Sprintf("Failed to set I/O priority %s to %s", name, strings.Join(failed, ", "))
+		m.statusIsError = true
+	} else {
+		m.status = fmt.Sprintf("Set I/O priority %s to %s", name, describeTargets(m.dialogTargets))
+		m.statusIsError = false
+	}
+
+	m.tagged = make(map[int32]struct{})
+	m.processesTable = m.processesTable.WithRows(generateProcessesTableRows(m))
+
+	return m
+}
+
+// describeTargets returns a short description of the targeted processes for
+// the dialogs and the status line.
+func describeTargets(pIds []int32) string {
+	if len(pIds) == 1 {
+		return fmt.Sprintf("PID %d", pIds[0])
+	}
+
+	return fmt.Sprintf("%d processes", len(pIds))
+}
+
+// dialogView renders the dialog being shown.
+func (m model) dialogView() string {
+	var s string
+
+	switch m.dialog {
+	case dialogSignal:
+		s = m.signalMenu.view()
+		s += "\n\n" + standardRowStyle.Render("enter to choose, esc to cancel")
+	case dialogSignalConfirm:
+		sig := signalOptions[m.signalMenu.cursor]
+		s = dialogTitleStyle.Render(fmt.Sprintf("Send %s to %s?", sig.name, describeTargets(m.dialogTargets)))
+		s += "\n\n" + standardRowStyle.Render("y to confirm, n to cancel")
+	case dialogNice:
+		s = dialogTitleStyle.Render(fmt.Sprintf("Nice value for %s:", describeTargets(m.dialogTargets)))
+		s += "\n\n" + m.niceInput.View()
+		s += "\n\n" + standardRowStyle.Render("enter to set, esc to cancel")
+	case dialogIOClass:
+		s = m.ioClassMenu.view()
+		s += "\n\n" + standardRowStyle.Render("enter to choose, esc to cancel")
+	case dialogIOLevel:
+		s = m.ioLevelMenu.view()
+		s += "\n\n" + standardRowStyle.Render("enter to set, esc to go back")
+	case dialogSort:
+		s = m.sortMenu.view()
+		s += "\n\n" + standardRowStyle.Render("enter to choose, esc to cancel")
+	}
+
+	return dialogStyle.Render(s)
+}
+
+// queryView renders the search or filter prompt being shown, or the active
+// filter otherwise.
+func (m model) queryView() string {
+	switch {
+	case m.dialog == dialogSearch:
+		return "\n " + dialogTitleStyle.Render("Search: ") + m.queryInput.View()
+	case m.dialog == dialogFilter:
+		return "\n " + dialogTitleStyle.Render("Filter: ") + m.queryInput.View()
+	case m.filter.active():
+		return "\n " + dialogTitleStyle.Render("Filter: ") + standardRowStyle.Render(m.filter.query)
+	}
+
+	return ""
+}
+
+// statusView renders the result of the last action taken by the user.
+func (m model) statusView() string {
+	if m.status == "" {
+		return ""
+	}
+
+	if m.statusIsError {
+		return "\n " + alertStyle.Render(m.status)
+	}
+
+	return "\n " + standardRowStyle.Render(m.status)
+}
@@ -0,0 +1,138 @@
+// File that describes the table of the files a process has open.
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/evertras/bubble-table/table"
+)
+
+// newFilesTable instantiates the open files table with its assigned columns,
+// filling the height left below the header. This is called only when the
+// application starts or it resizes.
+func newFilesTable(m model) table.Model {
+	columns := []table.Column{
+		table.NewFlexColumn("FD", "FD", columnDefaultFlexFactor),
+		table.NewFlexColumn("Mode", "Mode", columnDefaultFlexFactor),
+		table.NewFlexColumn("Type", "Type", columnDefaultFlexFactor),
+		table.NewFlexColumn("Node", "Node", columnLargerFlexFactor),
+		table.NewFlexColumn("Name", "Name", columnLargestFlexFactor*2),
+	}
+
+	// Lines taken by the header, the title, the filter, the help and the
+	// borders, title and footer of the table.
+	height := m.Height - 9
+	if m.Height >= minimumHeightTwoTables {
+		height -= headerTableHeight
+	}
+
+	return table.
+		New(columns).
+		BorderRounded().
+		WithBaseStyle(styleBase.Copy().Align(lipgloss.Left)).
+		WithTargetWidth(m.Width).
+		WithPageSize(max(height, 1)).
+		WithKeyMap(processesTableKeyMap()).
+		WithRows(generateFilesTableRows(m)).
+		Focused(true)
+}
+
+// generateFilesTableRows will generate all the rows that will be rendered
+// into the open files table, leaving out the files that don't contain the
+// filter in any column. This is called each time the application updates
+// while the table is shown.
+func generateFilesTableRows(m model) []table.Row {
+	var rows []table.Row
+
+	filter := strings.ToLower(m.filesFilter)
+	for _, file := range m.OpenFiles {
+		fd := strconv.Itoa(file.FD)
+		if filter != "" && !strings.Contains(strings.ToLower(strings.Join(
+			[]string{fd, file.Mode, file.Type, file.Node, file.Name}, " ")), filter) {
+			continue
+		}
+
+		rowData := make(table.RowData)
+		rowData["FD"] = fd
+		rowData["Mode"] = file.Mode
+		rowData["Type"] = file.Type
+		rowData["Node"] = file.Node
+		rowData["Name"] = file.Name
+
+		row := table.NewRow(rowData).WithStyle(standardRowStyle)
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// openOpenFiles shows the files the process of the detail view has open.
+func (m model) openOpenFiles() model {
+	m.screen = screenFiles
+	m.filesFilter = ""
+	m = m.updateOpenFiles()
+	m.filesTable = m.filesTable.WithHighlightedRow(0).PageFirst()
+
+	return m
+}
+
+// updateOpenFiles reads the files the shown process has open again.
+func (m model) updateOpenFiles() model {
+	m.OpenFiles, m.openFilesErr = getOpenFiles(m.detailPId)
+	m.filesTable = m.filesTable.WithRows(generateFilesTableRows(m))
+
+	return m
+}
+
+// openFilesFilterPrompt asks the user for the text the open files are
+// filtered by, starting with the current one.
+func (m model) openFilesFilterPrompt() (model, tea.Cmd) {
+	m.queryInput = textinput.New()
+	m.queryInput.Prompt = ""
+	m.queryInput.Placeholder = "text in any column, like TCP or .log"
+	m.queryInput.Width = m.Width / 2
+	m.queryInput.SetValue(m.filesFilter)
+	m.dialog = dialogFilesFilter
+
+	return m, m.queryInput.Focus()
+}
+
+// filterOpenFiles lists only the open files containing the given text.
+func (m model) filterOpenFiles(filter string) model {
+	m.filesFilter = filter
+	m.filesTable = m.filesTable.
+		WithRows(generateFilesTableRows(m)).
+		WithHighlightedRow(0).
+		PageFirst()
+
+	return m
+}
+
+// filesView renders the open files table with the filter, or why the files
+// can't be shown.
+func (m model) filesView() string {
+	title := fmt.Sprintf("Files open by process %d (%s)", m.detailPId, m.ProcessDetails.status("Name"))
+	if m.openFilesErr != nil {
+		return lipgloss.NewStyle().Padding(0, 1).Render(dialogTitleStyle.Render(title) + "\n" +
+			standardRowStyle.Render(fmt.Sprintf("The open files can't be shown: %v.", m.openFilesErr)))
+	}
+
+	title += fmt.Sprintf(": %d of %d", len(m.filesTable.GetVisibleRows()), len(m.OpenFiles))
+	s := lipgloss.NewStyle().Padding(0, 1).Render(dialogTitleStyle.Render(title) + "\n" + m.filesTable.View())
+
+	switch {
+	case m.dialog == dialogFilesFilter:
+		s += "\n " + dialogTitleStyle.Render("Filter: ") + m.queryInput.View()
+	case m.filesFilter != "":
+		s += "\n " + dialogTitleStyle.Render("Filter: ") + standardRowStyle.Render(m.filesFilter)
+	default:
+		s += "\n"
+	}
+
+	return s
+}
@@ -0,0 +1,156 @@
+// File that describes the graphs of the past usage of the system.
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// Rows of the CPU graph and of each memory graph.
+	cpuGraphHeight    = 6
+	memoryGraphHeight = 3
+)
+
+// graphBlocks are the characters a column of a graph is drawn with, from
+// empty to full in eighths.
+var graphBlocks = []rune(" ▁▂▃▄▅▆▇█")
+
+var (
+	graphBoxStyle = (lipgloss.
+			NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("#92DCE5")).
+			Padding(0, 1))
+
+	graphStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#207883"))
+)
+
+// resample spreads the whole capacity of the buffer over the given amount of
+// columns, so the graphs always show the same period of time whatever the
+// window's width. Each column is the average of the samples it covers, or
+// NaN when no sample was taken yet in that period.
+func resample(r *ringBuffer, width int) []float64 {
+	columns := make([]float64, width)
+	values := r.values()
+	capacity := r.capacity()
+	// Slot, within the capacity, of the oldest sample held.
+	first := capacity - len(values)
+
+	for i := range columns {
+		start := i * capacity / width
+		end := max((i+1)*capacity/width, start+1)
+
+		var sum float64
+		var count int
+		for slot := max(start, first); slot < end; slot++ {
+			sum += values[slot-first]
+			count++
+		}
+
+		if count == 0 {
+			columns[i] = math.NaN()
+		} else {
+			columns[i] = sum / float64(count)
+		}
+	}
+
+	return columns
+}
+
+// graphLines draws percentages as columns of blocks, height rows tall. The
+// lines are returned from top to bottom.
+func graphLines(columns []float64, height int) []string {
+	lines := make([]string, height)
+	for row := range lines {
+		// Eighths of a block below the bottom of this row.
+		bottom := (height - 1 - row) * 8
+
+		var line strings.Builder
+		for _, percent := range columns {
+			if math.IsNaN(percent) {
+				line.WriteRune(' ')
+				continue
+			}
+
+			eighths := int(math.Round(min(max(percent, 0), 100) / 100 * float64(height*8)))
+			line.WriteRune(graphBlocks[min(max(eighths-bottom, 0), 8)])
+		}
+		lines[row] = graphStyle.Render(line.String())
+	}
+
+	return lines
+}
+
+// graphBox draws the history of a percentage inside a box titled with its
+// latest value.
+func graphBox(title string, r *ringBuffer, width, height int) string {
+	s := dialogTitleStyle.Render(fmt.Sprintf("%s %05.2f%%", title, r.last()))
+	s += "\n" + strings.Join(graphLines(resample(r, width), height), "\n")
+
+	return graphBoxStyle.Width(width + 2).Render(s)
+}
+
+// sparkline draws the history of a percentage in a single line, after its
+// label and followed by its latest value.
+func sparkline(label string, labelWidth int, r *ringBuffer, width int) string {
+	value := fmt.Sprintf(" %05.2f%%", r.last())
+	graphWidth := max(width-labelWidth-len(value)-1, 1)
+
+	return standardRowStyle.Render(fmt.Sprintf("%-*s ", labelWidth, label)) +
+		graphLines(resample(r, graphWidth), 1)[0] +
+		standardRowStyle.Render(value)
+}
+
+// graphsView renders the history of the CPU, the memory, each core and each
+// disk, cutting it to the given amount of lines.
+func (m model) graphsView(lines int) string {
+	h := m.history
+	// Border and padding of the boxes.
+	width := max(m.Width-4, 1)
+	period := formatPeriod(m.opts.history)
+
+	var sections []string
+	sections = append(sections, graphBox("CPU over the last "+period+":", h.cpu, width, cpuGraphHeight))
+
+	halfWidth := max((m.Width-8)/2, 1)
+	sections = append(sections, lipgloss.JoinHorizontal(lipgloss.Top,
+		graphBox("Virtual Memory:", h.vMemory, halfWidth, memoryGraphHeight),
+		graphBox("Swap Memory:", h.sMemory, halfWidth, memoryGraphHeight),
+	))
+
+	var sparklines []string
+	for i, core := range h.cores {
+		sparklines = append(sparklines, sparkline(cpuLabel(i, len(h.cores)), 10, core, width))
+	}
+
+	labelWidth := 10
+	for _, mountPath := range h.diskOrder {
+		labelWidth = max(labelWidth, len(mountPath))
+	}
+	for _, mountPath := range h.diskOrder {
+		sparklines = append(sparklines, sparkline(mountPath, labelWidth, h.disks[mountPath], width))
+	}
+	sections = append(sections, graphBoxStyle.Width(width+2).Render(strings.Join(sparklines, "\n")))
+
+	all := strings.Split(lipgloss.NewStyle().Padding(0, 1).Render(strings.Join(sections, "\n")), "\n")
+	if len(all) > lines {
+		all = all[:max(lines, 0)]
+	}
+
+	return strings.Join(all, "\n")
+}
+
+// formatPeriod returns a period of time in whole minutes, or in seconds when
+// it is shorter than one.
+func formatPeriod(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%.0fs", d.Seconds())
+	}
+
+	return fmt.Sprintf("%.0fm", d.Minutes())
+}
@@ -0,0 +1,170 @@
+// File that describes the table of the memory mappings of a process.
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/evertras/bubble-table/table"
+)
+
+// Column the memory mappings are sorted by until the user picks another
+// one.
+const defaultMapsSortKey = "Rss"
+
+// mapsSortKeys are the columns of the memory maps table, in the order they
+// are cycled through when sorting.
+var mapsSortKeys = []string{"Address", "Perms", "Size", "Rss", "Pss", "Swap", "Dirty", "Name"}
+
+// mapsTableColumns returns the columns of the memory maps table, the one it
+// is sorted by showing the order. The address column counts the mappings of
+// each file instead when they are grouped.
+func mapsTableColumns(m model) []table.Column {
+	column := func(key, title string, flexFactor int) table.Column {
+		return table.NewFlexColumn(key, sortedTitle(key, title, m.mapsSortKey, m.mapsSortDesc), flexFactor)
+	}
+
+	address := column("Address", "Address", columnHugeFlexFactor)
+	if m.mapsByFile {
+		address = column("Address", "Mappings", columnHugeFlexFactor)
+	}
+
+	return []table.Column{
+		address,
+		column("Perms", "Perms", columnDefaultFlexFactor),
+		column("Size", "Size", columnLargerFlexFactor),
+		column("Rss", "RSS", columnLargerFlexFactor),
+		column("Pss", "PSS", columnLargerFlexFactor),
+		column("Swap", "Swap", columnLargerFlexFactor),
+		column("Dirty", "Dirty", columnLargerFlexFactor),
+		column("Name", "Name", columnLargestFlexFactor*2),
+	}
+}
+
+// newMapsTable instantiates the memory maps table with its assigned columns,
+// filling the height left below the header. This is called only when the
+// application starts or it resizes.
+func newMapsTable(m model) table.Model {
+	// Lines taken by the header, the title, the help and the borders, title
+	// and footer of the table.
+	height := m.Height - 8
+	if m.Height >= minimumHeightTwoTables {
+		height -= headerTableHeight
+	}
+
+	return table.
+		New(mapsTableColumns(m)).
+		BorderRounded().
+		WithBaseStyle(styleBase.Copy().Align(lipgloss.Left)).
+		WithTargetWidth(m.Width).
+		WithPageSize(max(height, 1)).
+		WithKeyMap(processesTableKeyMap()).
+		WithRows(generateMapsTableRows(m)).
+		Focused(true)
+}
+
+// generateMapsTableRows will generate all the rows that will be rendered
+// into the memory maps table, sorted by the column chosen by the user. This
+// is called each time the application updates while the table is shown.
+func generateMapsTableRows(m model) []table.Row {
+	var rows []table.Row
+
+	mappings := append([]memoryMapping(nil), m.MemoryMaps.Mappings...)
+	if m.mapsByFile {
+		mappings = groupMappings(mappings)
+	}
+	sortMappings(mappings, m.mapsSortKey, m.mapsSortDesc)
+
+	units := m.opts.units
+	for _, mapping := range mappings {
+		rowData := make(table.RowData)
+		rowData["Address"] = mapping.Address
+		if m.mapsByFile {
+			rowData["Address"] = fmt.Sprint(mapping.Count)
+		}
+		rowData["Perms"] = mapping.Perms
+		rowData["Size"] = units.format(mapping.Size)
+		rowData["Rss"] = units.format(mapping.Rss)
+		rowData["Pss"] = units.format(mapping.Pss)
+		rowData["Swap"] = units.format(mapping.Swap)
+		rowData["Dirty"] = units.format(mapping.Dirty)
+		rowData["Name"] = mapping.Name
+
+		row := table.NewRow(rowData).WithStyle(standardRowStyle)
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// openMemoryMaps shows the memory mappings of the process of the detail
+// view.
+func (m model) openMemoryMaps() model {
+	m.screen = screenMaps
+	m = m.updateMemoryMaps()
+	m.mapsTable = m.mapsTable.WithHighlightedRow(0).PageFirst()
+
+	return m
+}
+
+// updateMemoryMaps reads the memory mappings of the shown process again.
+func (m model) updateMemoryMaps() model {
+	m.MemoryMaps, m.memoryMapsErr = getMemoryMaps(m.detailPId)
+	m.mapsTable = m.mapsTable.WithRows(generateMapsTableRows(m))
+
+	return m
+}
+
+// sortMapsTable applies the sort chosen by the user to the memory maps
+// table, starting from its first row.
+func (m model) sortMapsTable() model {
+	m.mapsTable = m.mapsTable.
+		WithColumns(mapsTableColumns(m)).
+		WithRows(generateMapsTableRows(m)).
+		WithHighlightedRow(0).
+		PageFirst()
+
+	return m
+}
+
+// cycleMapsSort sorts the memory maps table by its next column. The sizes
+// are sorted from the largest, the rest in ascending order.
+func (m model) cycleMapsSort() model {
+	next := 0
+	for i, key := range mapsSortKeys {
+		if key == m.mapsSortKey {
+			next = (i + 1) % len(mapsSortKeys)
+		}
+	}
+
+	m.mapsSortKey = mapsSortKeys[next]
+	m.mapsSortDesc = m.mapsSortKey != "Address" && m.mapsSortKey != "Perms" && m.mapsSortKey != "Name"
+
+	return m.sortMapsTable()
+}
+
+// toggleMapsByFile switches between a row for each memory mapping and a row
+// for each file, adding up the sizes of its mappings.
+func (m model) toggleMapsByFile() model {
+	m.mapsByFile = !m.mapsByFile
+
+	return m.sortMapsTable()
+}
+
+// mapsView renders the memory maps table with the totals of the process, or
+// why the mappings can't be shown.
+func (m model) mapsView() string {
+	title := fmt.Sprintf("Memory maps of process %d (%s)", m.detailPId, m.ProcessDetails.status("Name"))
+	if m.memoryMapsErr != nil {
+		return lipgloss.NewStyle().Padding(0, 1).Render(dialogTitleStyle.Render(title) + "\n" +
+			standardRowStyle.Render(fmt.Sprintf("The memory maps can't be shown: %v.", m.memoryMapsErr)))
+	}
+
+	units := m.opts.units
+	total := m.MemoryMaps.Total
+	title += fmt.Sprintf(": %d mappings, size %s, RSS %s, PSS %s, swap %s, dirty %s", total.Count,
+		units.format(total.Size), units.format(total.Rss), units.format(total.Pss),
+		units.format(total.Swap), units.format(total.Dirty))
+
+	return lipgloss.NewStyle().Padding(0, 1).Render(dialogTitleStyle.Render(title) + "\n" + m.mapsTable.View())
+}
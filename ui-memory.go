@@ -0,0 +1,136 @@
+// File that describes the breakdown of the memory usage.
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Width of each name and value pair of the memory details view.
+const memoryDetailWidth = 34
+
+// memorySegments are the parts the virtual memory bar is split by, in the
+// order they are drawn, like htop does.
+var memorySegments = []struct {
+	name  string
+	style lipgloss.Style
+	value func(i memoryInfo) uint64
+}{
+	{"used", lipgloss.NewStyle().Foreground(lipgloss.Color("#207883")), func(i memoryInfo) uint64 { return i.Used }},
+	{"buffers", lipgloss.NewStyle().Foreground(lipgloss.Color("#5C7AEA")), func(i memoryInfo) uint64 { return i.Buffers }},
+	{"shared", lipgloss.NewStyle().Foreground(lipgloss.Color("#C792EA")), func(i memoryInfo) uint64 { return i.Shared }},
+	// The shared memory is accounted as cache by the kernel.
+	{"cache", lipgloss.NewStyle().Foreground(lipgloss.Color("#FFE066")), func(i memoryInfo) uint64 {
+		if i.Cached < i.Shared {
+			return 0
+		}
+		return i.Cached - i.Shared
+	}},
+}
+
+// memoryBar renders the virtual memory as a bar split in segments, one for
+// each part of the memory in use.
+func memoryBar(info memoryInfo, width int) string {
+	if info.Total == 0 {
+		return cpuEmptyStyle.Render(strings.Repeat("░", width))
+	}
+
+	// Each segment ends where the accumulated size does, so rounding doesn't
+	// make the bar longer than its width.
+	var bar strings.Builder
+	var accumulated uint64
+	var filled int
+	for _, segment := range memorySegments {
+		accumulated += segment.value(info)
+		end := min(int(math.Round(float64(accumulated)/float64(info.Total)*float64(width))), width)
+		if end > filled {
+			bar.WriteString(segment.style.Render(strings.Repeat("█", end-filled)))
+			filled = end
+		}
+	}
+	bar.WriteString(cpuEmptyStyle.Render(strings.Repeat("░", width-filled)))
+
+	return bar.String()
+}
+
+// memoryLegend names the segments of the memory bar. The sizes of each one
+// and the available memory are shown when withSizes is set.
+func memoryLegend(info memoryInfo, units sizeUnits, withSizes bool) string {
+	var parts []string
+	for _, segment := range memorySegments {
+		part := segment.style.Render("█") + " " + segment.name
+		if withSizes {
+			part += " " + units.format(segment.value(info))
+		}
+		parts = append(parts, part)
+	}
+
+	available := "avail " + units.format(info.Available)
+
+	return standardRowStyle.Render(strings.Join(parts, "  ") + "  " + available)
+}
+
+// memoryDetailsView renders the breakdown of the virtual memory, the memory
+// accounting of the kernel and the usage of the zram devices, cutting it to
+// the given amount of lines.
+func (m model) memoryDetailsView(lines int) string {
+	// Border and padding of the boxes.
+	width := max(m.Width-4, 1)
+	units := m.opts.units
+
+	var sections []string
+	breakdown := dialogTitleStyle.Render("Virtual Memory: "+formatMemoryUsage(m.VMemoryInfo, units)) + "\n" +
+		memoryBar(m.VMemoryInfo, width) + "\n" +
+		memoryLegend(m.VMemoryInfo, units, true)
+	sections = append(sections, graphBoxStyle.Width(width+2).Render(breakdown))
+
+	details := dialogTitleStyle.Render("Kernel memory accounting:") + "\n"
+	if len(m.MemoryDetails.Entries) == 0 {
+		details += standardRowStyle.Render("Not available on this system.")
+	} else {
+		details += memoryDetailsGrid(m.MemoryDetails.Entries, units, width)
+	}
+	sections = append(sections, graphBoxStyle.Width(width+2).Render(details))
+
+	if len(m.MemoryDetails.Zram) > 0 {
+		zram := []string{dialogTitleStyle.Render("zram devices:")}
+		for _, z := range m.MemoryDetails.Zram {
+			ratio := "-"
+			if z.CompressedSize > 0 {
+				ratio = fmt.Sprintf("%.2f", float64(z.OriginalSize)/float64(z.CompressedSize))
+			}
+			zram = append(zram, standardRowStyle.Render(fmt.Sprintf("%-8s size %-10s data %-10s compressed %-10s ratio %-6s memory used %s",
+				z.Device, units.format(z.DiskSize), units.format(z.OriginalSize),
+				units.format(z.CompressedSize), ratio, units.format(z.MemoryUsed))))
+		}
+		sections = append(sections, graphBoxStyle.Width(width+2).Render(strings.Join(zram, "\n")))
+	}
+
+	all := strings.Split(lipgloss.NewStyle().Padding(0, 1).Render(strings.Join(sections, "\n")), "\n")
+	if len(all) > lines {
+		all = all[:max(lines, 0)]
+	}
+
+	return strings.Join(all, "\n")
+}
+
+// memoryDetailsGrid lays out the memory details in as many columns as fit the
+// width, filling each column from top to bottom.
+func memoryDetailsGrid(entries []memoryDetail, units sizeUnits, width int) string {
+	columns := max(width/memoryDetailWidth, 1)
+	rows := ceilDiv(len(entries), columns)
+
+	lines := make([]string, rows)
+	for i, entry := range entries {
+		value := fmt.Sprint(entry.Value)
+		if entry.IsSize {
+			value = units.format(entry.Value)
+		}
+		lines[i%rows] += fmt.Sprintf("%-16s %-*s", entry.Name+":", memoryDetailWidth-17, value)
+	}
+
+	return standardRowStyle.Render(strings.Join(lines, "\n"))
+}
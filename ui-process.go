@@ -0,0 +1,201 @@
+// File that describes the detail view of a single process.
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// capabilitySet is a capability set of the status file of a process.
+type capabilitySet struct {
+	// field is the line of the status file with the mask of the set.
+	field string
+	// label is the name the set is shown with.
+	label string
+}
+
+// processStatusCapabilities are the capability sets of the status file, in
+// the order they are shown.
+var processStatusCapabilities = []capabilitySet{
+	{field: "CapEff", label: "Effective"},
+	{field: "CapPrm", label: "Permitted"},
+	{field: "CapInh", label: "Inheritable"},
+	{field: "CapBnd", label: "Bounding"},
+	{field: "CapAmb", label: "Ambient"},
+}
+
+// newDetailViewport instantiates the scrollable area the details of a process
+// are shown in, filling the height left below the header. Only the arrows and
+// the page keys scroll it, as the rest are taken by the application. This is
+// called only when the application starts or it resizes.
+func newDetailViewport(m model) viewport.Model {
+	// Lines taken by the help and the status line.
+	height := m.Height - 2
+	if m.Height >= minimumHeightTwoTables {
+		height -= headerTableHeight
+	}
+
+	v := viewport.New(m.Width, max(height, 1))
+	v.KeyMap = viewport.KeyMap{
+		PageDown:     key.NewBinding(key.WithKeys("pgdown")),
+		PageUp:       key.NewBinding(key.WithKeys("pgup")),
+		HalfPageUp:   key.NewBinding(key.WithDisabled()),
+		HalfPageDown: key.NewBinding(key.WithDisabled()),
+		Up:           key.NewBinding(key.WithKeys("up")),
+		Down:         key.NewBinding(key.WithKeys("down")),
+	}
+	v.SetContent(processDetailsContent(m))
+
+	return v
+}
+
+// processDetailsView renders the details of the shown process, or the dialog
+// acting on it in their place.
+func (m model) processDetailsView() string {
+	if m.dialog != dialogNone && !m.dialog.inline() {
+		return lipgloss.NewStyle().Height(m.detailViewport.Height).Padding(1).Render(m.dialogView())
+	}
+
+	return m.detailViewport.View()
+}
+
+// openProcessDetails shows the details of the highlighted process in place of
+// the tables.
+func (m model) openProcessDetails() model {
+	if m.tablesHeight() < minimumHeightAllTables || len(m.processesTable.GetVisibleRows()) == 0 {
+		return m
+	}
+
+	pId, ok := m.processesTable.HighlightedRow().Data["PId"].(int32)
+	if !ok {
+		return m
+	}
+
+	m.screen = screenProcess
+	m.detailPId = pId
+	m.detailViewport.GotoTop()
+
+	return m.updateProcessDetails()
+}
+
+// updateProcessDetails reads the details of the shown process again. When it
+// exited the last details read are kept.
+func (m model) updateProcessDetails() model {
+	details, err := getProcessDetails(m.detailPId)
+	if err == nil || m.ProcessDetails.PId != m.detailPId {
+		m.ProcessDetails = details
+	}
+	m.processDetailsErr = err
+	m.detailViewport.SetContent(processDetailsContent(m))
+
+	return m
+}
+
+// processDetailsContent renders every detail of the shown process, to be
+// scrolled through.
+func processDetailsContent(m model) string {
+	d := m.ProcessDetails
+	width := max(m.Width-2, 1)
+
+	var process processInfo
+	processes := make(map[int32]processInfo, len(m.Processes))
+	for _, p := range m.Processes {
+		processes[p.PId] = p
+		if p.PId == m.detailPId {
+			process = p
+		}
+	}
+
+	var sections []string
+	section := func(title string, lines ...string) {
+		if len(lines) == 0 || len(lines) == 1 && lines[0] == "" {
+			lines = []string{"Not available."}
+		}
+		body := standardRowStyle.Copy().Width(width).Render(strings.Join(lines, "\n"))
+		sections = append(sections, dialogTitleStyle.Render(title)+"\n"+body)
+	}
+
+	title := fmt.Sprintf("Process %d (%s)", m.detailPId, d.status("Name"))
+	if m.processDetailsErr != nil {
+		title += " " + alertStyle.Render(fmt.Sprintf("can't be read: %v", m.processDetailsErr))
+		if d.PId == m.detailPId {
+			title += alertStyle.Render(", showing its last details")
+		}
+	}
+	sections = append(sections, dialogTitleStyle.Render(title))
+	if d.PId != m.detailPId {
+		return lipgloss.NewStyle().Padding(0, 1).Render(strings.Join(sections, "\n\n"))
+	}
+
+	section("Command line:", strings.Join(d.Args, " "))
+	section("Executable:", d.Exe)
+	section("Working directory:", d.Cwd)
+
+	var parents []string
+	for pId, seen := process.PPId, 0; seen < len(processes); seen++ {
+		parent, ok := processes[pId]
+		if !ok || parent.PId == parent.PPId {
+			break
+		}
+		parents = append(parents, fmt.Sprintf("%d %s", parent.PId, parent.Name))
+		pId = parent.PPId
+	}
+	if len(parents) == 0 {
+		parents = []string{"None listed."}
+	}
+	section("Parents:", strings.Join(parents, " ← "))
+
+	section("User and groups:",
+		fmt.Sprintf("User: %s", process.User),
+		fmt.Sprintf("Uid (real, effective, saved, filesystem): %s", d.status("Uid")),
+		fmt.Sprintf("Gid (real, effective, saved, filesystem): %s", d.status("Gid")),
+		fmt.Sprintf("Groups: %s", d.status("Groups")))
+
+	var caps []string
+	for _, set := range processStatusCapabilities {
+		if mask := d.status(set.field); mask != "" {
+			caps = append(caps, fmt.Sprintf("%-12s %s", set.label+":", capabilities(mask)))
+		}
+	}
+	section("Capabilities:", caps...)
+
+	section("Cgroups:", d.Cgroups...)
+
+	var namespaces []string
+	for _, ns := range d.Namespaces {
+		namespaces = append(namespaces, ns.Value)
+	}
+	section("Namespaces:", strings.Join(namespaces, "  "))
+
+	section("Limits:", d.Limits...)
+
+	var status []string
+	for _, field := range d.Status {
+		status = append(status, fmt.Sprintf("%-28s %s", field.Name+":", field.Value))
+	}
+	section("Status:", status...)
+
+	if d.EnvironErr != nil {
+		section("Environment:", fmt.Sprintf("Not readable: %v.", d.EnvironErr))
+	} else {
+		section("Environment:", d.Environ...)
+	}
+
+	return lipgloss.NewStyle().Padding(0, 1).Render(strings.Join(sections, "\n\n"))
+}
+
+// status returns the value of a field of the status file, or nothing when it
+// isn't there.
+func (d processDetails) status(name string) string {
+	for _, field := range d.Status {
+		if field.Name == name {
+			return field.Value
+		}
+	}
+
+	return ""
+}
@@ -4,15 +4,31 @@ import (
 	"fmt"
 	"math"
 	"runtime"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/evertras/bubble-table/table"
 )
 
 const (
+	columnKeyHeaderTable = "headerTable"
+
 	cpuTableTitle = "CPU Usage Percentage"
-	// Amount of columns in one row.
+	// Amount of columns in one row, unless the cores need more rows than
+	// cpuTableMaxRowAmount.
 	cpuTableMaxColumnAmount = 4
+	cpuTableMaxRowAmount    = 8
+	// Narrowest progress bar of a core, including its percentage.
+	cpuProgressMinWidth = 15
+	// Width of the progress bars relative to the window's width.
+	cpuProgressWidthRatio = 0.15
+	// Width taken by the borders and padding of the CPU table.
+	cpuTableMargin = 4
+	// Format of the total usage shown after each bar of the CPU table.
+	cpuPercentFormat = " %05.2f%% "
 
 	columnKeyCpuTable = "cpuTable"
 
@@ -20,8 +36,71 @@ const (
 	columnKeyVirtualMemoryTitle = "Virtual Memory"
 	columnKeySwapMemory         = "swapMemory"
 	columnKeySwapMemoryTitle    = "Swap Memory"
+
+	// Column the processes are sorted by when the application starts, in
+	// descending order.
+	defaultProcessesSortKey = "CpuPercentage"
+	// Column the disks are sorted by when the application starts, in
+	// ascending order. Ties are broken by the mount path.
+	defaultDisksSortKey = "FsType"
+
+	// Percentage of the time stalled from which the pressure of a resource
+	// is highlighted as a warning and as an alert.
+	pressureWarning = 10
+	pressureAlert   = 40
+
+	// Percentage of the time busy from which a device is highlighted as
+	// saturated.
+	saturatedUtilization = 90
+
+	// Percentage of the size used from which a file system is highlighted
+	// as almost full.
+	fullDiskPercent = 90
+	// Width of the used percentage bar of each file system, including the
+	// percentage.
+	diskUsageBarWidth = 12
+
+	// Share of the window's width taken by the disks table, the network
+	// table is shown next to it.
+	disksTableWidthRatio = 0.65
+
+	sortDescArrow = " ▼"
+	sortAscArrow  = " ▲"
 )
 
+// processesSortOptions are the columns listed in the sort menu of the
+// processes table.
+var processesSortOptions = []struct {
+	key  string
+	name string
+}{
+	{"PId", "PID"},
+	{"PPId", "Parent PID"},
+	{"User", "User"},
+	{"Priority", "Priority"},
+	{"Nice", "Nice"},
+	{"State", "State"},
+	{"Threads", "Threads"},
+	{"CpuPercentage", "CPU%"},
+	{"MemPercentage", "MEM%"},
+	{"Resident", "Resident memory"},
+	{"Virtual", "Virtual memory"},
+	{"Shared", "Shared memory"},
+	{"StartTime", "Start time"},
+	{"CpuTime", "CPU time"},
+	{"Container", "Container"},
+	{"IOReadRate", "I/O read rate"},
+	{"IOWriteRate", "I/O write rate"},
+	{"Name", "Name"},
+	{"ExeP", "Executable path"},
+	{"Cmdline", "Command"},
+}
+
+// disksSortKeys are the columns of the disks table, in the order they are
+// cycled through when choosing the one to sort by.
+var disksSortKeys = []string{"FsType", "Device", "MountPath", "TotalSize", "FreeSize", "UsedSize",
+	"UsedPercent", "InodesTotal", "InodesUsed", "InodesFree", "ReadRate", "WriteRate", "IOPS", "Utilization", "Await"}
+
 const (
 	// https://pkg.go.dev/github.com/evertras/bubble-table/table?utm_source=gopls#NewFlexColumn
 	columnDefaultFlexFactor = 1
@@ -37,8 +116,143 @@ var (
 			Align(lipgloss.Center))
 
 	standardRowStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#CEEFF3"))
+	// Used for values that need the user's attention, like zombie processes.
+	alertStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#F25F5C")).Bold(true)
+	// Used for values approaching a problem, like some pressure on a resource.
+	warningStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#F79D65"))
+	// Used for the processes tagged by the user.
+	taggedRowStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFE066")).Bold(true)
+
+	cpuPercentStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#EFFAFB"))
+	cpuEmptyStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#606060"))
 )
 
+// cpuSegments are the states the usage bars of the CPU are split by, in the
+// order they are drawn.
+var cpuSegments = []struct {
+	name  string
+	style lipgloss.Style
+	value func(u cpuUsage) float64
+}{
+	{"user", lipgloss.NewStyle().Foreground(lipgloss.Color("#207883")), func(u cpuUsage) float64 { return u.User }},
+	{"nice", lipgloss.NewStyle().Foreground(lipgloss.Color("#92DCE5")), func(u cpuUsage) float64 { return u.Nice }},
+	{"system", lipgloss.NewStyle().Foreground(lipgloss.Color("#F25F5C")), func(u cpuUsage) float64 { return u.System }},
+	{"iowait", lipgloss.NewStyle().Foreground(lipgloss.Color("#8D99AE")), func(u cpuUsage) float64 { return u.IOWait }},
+	{"irq", lipgloss.NewStyle().Foreground(lipgloss.Color("#C792EA")), func(u cpuUsage) float64 { return u.Irq }},
+	{"steal", lipgloss.NewStyle().Foreground(lipgloss.Color("#F79D65")), func(u cpuUsage) float64 { return u.Steal }},
+}
+
+// newHeaderTable instantiates the summary table shown above the CPU table.
+// This is called only when the application starts or it resizes.
+func newHeaderTable(m model) table.Model {
+	columns := []table.Column{
+		table.NewFlexColumn(columnKeyHeaderTable, "", columnDefaultFlexFactor),
+	}
+
+	return table.
+		New(columns).
+		BorderRounded().
+		WithBaseStyle(styleBase.Copy().Align(lipgloss.Left)).
+		WithHeaderVisibility(false).
+		WithTargetWidth(m.Width).
+		WithRows(generateHeaderTableRows(m))
+}
+
+// generateHeaderTableRows generates the rows of the summary table: the task
+// counts, load average and uptime in the first one, the frequency of the
+// cores in the second and the pressure stall information in the third. This
+// is called each time the application updates.
+func generateHeaderTableRows(m model) []table.Row {
+	tasks := countTasks(m.Processes)
+	summary := fmt.Sprintf("Tasks: %d, %d thr; %d running, %d sleeping, %d stopped",
+		tasks.Total, tasks.Threads, tasks.Running, tasks.Sleeping, tasks.Stopped)
+	if tasks.Zombie > 0 {
+		summary = standardRowStyle.Render(summary+", ") + alertStyle.Render(fmt.Sprintf("%d zombie", tasks.Zombie))
+	} else {
+		summary = standardRowStyle.Render(summary)
+	}
+
+	info := m.SystemInfo
+	summary += standardRowStyle.Render(fmt.Sprintf("   Load average: %.2f %.2f %.2f   Uptime: %s",
+		info.Load1, info.Load5, info.Load15, formatUptime(info.Uptime)))
+
+	frequency := "CPU frequency: not reported"
+	if len(info.Frequencies) > 0 {
+		var sum float64
+		cores := make([]string, len(info.Frequencies))
+		for i, f := range info.Frequencies {
+			sum += f.MHz
+			cores[i] = fmt.Sprintf("#%02d %.0f", f.Core, f.MHz)
+		}
+
+		frequency = fmt.Sprintf("CPU frequency: %.0f MHz avg   %s",
+			sum/float64(len(info.Frequencies)), strings.Join(cores, "  "))
+	}
+
+	rows := []table.Row{
+		table.NewRow(table.RowData{columnKeyHeaderTable: summary}),
+		table.NewRow(table.RowData{columnKeyHeaderTable: frequency}).WithStyle(standardRowStyle),
+		table.NewRow(table.RowData{columnKeyHeaderTable: pressureSummary(info.Pressure)}),
+	}
+
+	return rows
+}
+
+// pressureSummary returns the pressure stall averages over 10, 60 and 300
+// seconds of each resource, colored by how stalled it is.
+func pressureSummary(pressures []pressure) string {
+	if len(pressures) == 0 {
+		return standardRowStyle.Render("Pressure stall: not reported by the kernel")
+	}
+
+	stall := func(kind string, s pressureStall) string {
+		return standardRowStyle.Render(" "+kind+" ") + pressureValue(s.Avg10) +
+			standardRowStyle.Render("/") + pressureValue(s.Avg60) +
+			standardRowStyle.Render("/") + pressureValue(s.Avg300)
+	}
+
+	summary := standardRowStyle.Render("Pressure stall (avg10/60/300):")
+	for _, p := range pressures {
+		summary += standardRowStyle.Render("   "+p.Resource) + stall("some", p.Some)
+		if p.HasFull {
+			summary += stall("full", p.Full)
+		}
+	}
+
+	return summary
+}
+
+// pressureValue renders a pressure stall percentage, highlighted when it is
+// high.
+func pressureValue(percent float64) string {
+	value := fmt.Sprintf("%.2f", percent)
+
+	switch {
+	case percent >= pressureAlert:
+		return alertStyle.Render(value)
+	case percent >= pressureWarning:
+		return warningStyle.Render(value)
+	default:
+		return standardRowStyle.Render(value)
+	}
+}
+
+// formatUptime returns the uptime as days followed by hours:minutes:seconds,
+// like htop does.
+func formatUptime(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	clock := fmt.Sprintf("%02d:%02d:%02d", int(d.Hours())%24, int(d.Minutes())%60, int(d.Seconds())%60)
+
+	switch {
+	case days == 1:
+		return "1 day, " + clock
+	case days > 1:
+		return fmt.Sprintf("%d days, %s", days, clock)
+	default:
+		return clock
+	}
+}
+
 // newCpuTable instantiates the CPU information table with its assigned
 // columns. This is called only when the application starts or it resizes.
 func newCpuTable(m model) table.Model {
@@ -54,53 +268,145 @@ func newCpuTable(m model) table.Model {
 		WithTargetWidth(m.Width)
 }
 
+// cpuTableLayout returns the amount of columns the cores are laid out in, the
+// amount of cores each progress bar shows and the width of the bars for the
+// given window's width. Up to cpuTableMaxColumnAmount columns are used,
+// adding more only when the rows would exceed cpuTableMaxRowAmount, as long
+// as they fit in the window. When the rows would still exceed it, each bar
+// shows the average of several consecutive cores, so the CPU table is never
+// taller than the minimum heights of the window allow for.
+func cpuTableLayout(cores, width int) (columns, group, progressWidth int) {
+	if cores <= 0 {
+		return 1, 1, cpuProgressMinWidth
+	}
+
+	group = 1
+	labelWidth := len(cpuLabel(cores-1, cores)) + 1
+	available := width - cpuTableMargin
+	maxColumns := max(available/(labelWidth+cpuProgressMinWidth), 1)
+	if ceilDiv(cores, maxColumns) > cpuTableMaxRowAmount {
+		labelWidth = len(cpuRangeLabel(0, 1, cores)) + 1
+		maxColumns = max(available/(labelWidth+cpuProgressMinWidth), 1)
+		group = ceilDiv(cores, maxColumns*cpuTableMaxRowAmount)
+	}
+	bars := ceilDiv(cores, group)
+
+	columns = cpuTableMaxColumnAmount
+	if rows := ceilDiv(bars, columns); rows > cpuTableMaxRowAmount {
+		columns = ceilDiv(bars, cpuTableMaxRowAmount)
+	}
+	columns = min(columns, maxColumns, bars)
+	// Columns left empty after laying out the bars are dropped, e.g. 6 cores
+	// take 2 rows of 3 columns rather than 2 rows of 4.
+	columns = ceilDiv(bars, ceilDiv(bars, columns))
+
+	// Bars are as wide as they used to be, a fraction of the window, unless
+	// there is no room for them.
+	progressWidth = max(int(float64(width)*cpuProgressWidthRatio), cpuProgressMinWidth)
+	progressWidth = min(progressWidth, available/columns-labelWidth)
+	progressWidth = max(progressWidth, 1)
+
+	return columns, group, progressWidth
+}
+
+// cpuLabel returns the label of a core, with as many digits as the highest
+// core index needs.
+func cpuLabel(index, cores int) string {
+	digits := max(len(strconv.Itoa(cores-1)), 2)
+	return fmt.Sprintf("CPU #%0*d:", digits, index)
+}
+
+// cpuRangeLabel returns the label of a bar showing the cores from first to
+// last, with as many digits as the highest core index needs.
+func cpuRangeLabel(first, last, cores int) string {
+	digits := max(len(strconv.Itoa(cores-1)), 2)
+	return fmt.Sprintf("CPU #%0*d-%0*d:", digits, first, digits, last)
+}
+
+// averageCpuUsage returns the mean usage of the given cores.
+func averageCpuUsage(cores []cpuUsage) cpuUsage {
+	var avg cpuUsage
+	for _, core := range cores {
+		avg.User += core.User
+		avg.Nice += core.Nice
+		avg.System += core.System
+		avg.IOWait += core.IOWait
+		avg.Irq += core.Irq
+		avg.Steal += core.Steal
+	}
+
+	n := float64(max(len(cores), 1))
+	avg.User /= n
+	avg.Nice /= n
+	avg.System /= n
+	avg.IOWait /= n
+	avg.Irq /= n
+	avg.Steal /= n
+
+	return avg
+}
+
+// ceilDiv returns a / b rounded up.
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
+
 // generateCpuTableRows generates all the rows that will be rendered into
 // the CPU information table. This is called each time the application updates.
 func generateCpuTableRows(m model) []table.Row {
-	cpuFmt := "CPU #%02d:"
-	// The number of cores divided by the number of chosen columns will be
-	// ceiled in order to calculate a fixed number of rows in which to display
-	// all the CPU info in an uniform manner.
-	rowCount := int(math.Ceil(float64(len(m.CpuInfo)) / cpuTableMaxColumnAmount))
+	cores := len(m.CpuInfo)
+	if cores == 0 {
+		return nil
+	}
 
-	// When the number of cpuTableMaxColumnAmount doesn't satisfy the equation
-	// len(m.CpuInfo) / rowCount = cpuTableMaxColumnAmount, the program can't
-	// display all cores properly.
-	// TODO: Support core counts that are not divisible by 4.
-	if rowCount*cpuTableMaxColumnAmount != len(m.CpuInfo) {
-		s := "The amount of columns or rows is incorrect.\n"
-		s += "TODO: Support core counts that are not divisible by 4.\n"
-		s = fmt.Sprintf("%sThe product of both should be %d.", s, len(m.CpuInfo))
+	columns := max(m.cpuColumns, 1)
+	group := max(m.cpuGroup, 1)
+	// The bars are laid out by columns, each one with the same amount of
+	// rows except the last, which is left ragged.
+	bars := ceilDiv(cores, group)
+	rowCount := ceilDiv(bars, columns)
+	labelWidth := len(cpuLabel(cores-1, cores)) + 1
+	if group > 1 {
+		labelWidth = len(cpuRangeLabel(0, 1, cores)) + 1
+	}
+	blank := strings.Repeat(" ", labelWidth+m.cpuBarWidth)
 
-		panic(s)
+	// The usage of the whole CPU goes first, as wide as all the columns.
+	allLabel := fmt.Sprintf("%-*s", labelWidth-1, "CPU all:")
+	allWidth := columns*(labelWidth+m.cpuBarWidth) - labelWidth
+	rows := []table.Row{
+		table.NewRow(table.RowData{
+			columnKeyCpuTable: standardRowStyle.Render(allLabel) + " " + cpuBar(m.CpuAll, allWidth),
+		}),
 	}
 
 	// Each bubble-tea row is a string containing all the contents of the columns.
-	// For example, using a 12 core CPU and 4 columns each row:
-	// Row 0 : "CPU #00 m.cpuProgresses[0] CPU #03 m.cpuProgresses[3] CPU #06 m.cpuProgresses[6] CPU #09 m.cpuProgresses[9]"
-	// Row 1 : "CPU #01 m.cpuProgresses[1] CPU #04 m.cpuProgresses[4] CPU #07 m.cpuProgresses[7] CPU #10 m.cpuProgresses[10]"
-	// Row 2 : "CPU #02 m.cpuProgresses[2] CPU #05 m.cpuProgresses[5] CPU #08 m.cpuProgresses[8] CPU #11 m.cpuProgresses[11]"
-	var rows []table.Row
+	// For example, using a 10 core CPU and 4 columns each row:
+	// Row 0 : "CPU #00 cpuBar(m.CpuInfo[0]) CPU #03 cpuBar(m.CpuInfo[3]) CPU #06 cpuBar(m.CpuInfo[6]) CPU #09 cpuBar(m.CpuInfo[9])"
+	// Row 1 : "CPU #01 cpuBar(m.CpuInfo[1]) CPU #04 cpuBar(m.CpuInfo[4]) CPU #07 cpuBar(m.CpuInfo[7])"
+	// Row 2 : "CPU #02 cpuBar(m.CpuInfo[2]) CPU #05 cpuBar(m.CpuInfo[5]) CPU #08 cpuBar(m.CpuInfo[8])"
 	for i := 0; i < rowCount; i++ {
 		r := ""
-		for c := 0; c < cpuTableMaxColumnAmount; c++ {
-			if c > 0 {
-				// index of the row + index of the column * the amount of values
-				// in one column.
-				index := i + c*rowCount
-
-				r += fmt.Sprintf(
-					"%s %s",
-					standardRowStyle.SetString(fmt.Sprintf(cpuFmt, index)).String(),
-					m.cpuProgresses[index].ViewAs(m.CpuInfo[i]/100),
-				)
-			} else {
-				r += fmt.Sprintf(
-					"%s %s",
-					standardRowStyle.SetString(fmt.Sprintf(cpuFmt, i)).String(),
-					m.cpuProgresses[i].ViewAs(m.CpuInfo[i]/100),
-				)
+		for c := 0; c < columns; c++ {
+			// index of the row + index of the column * the amount of values
+			// in one column.
+			index := i + c*rowCount
+			if index >= bars {
+				r += blank
+				continue
 			}
+
+			label, usage := cpuLabel(index, cores), m.CpuInfo[index]
+			if group > 1 {
+				first, last := index*group, min((index+1)*group, cores)-1
+				label, usage = cpuRangeLabel(first, last, cores), averageCpuUsage(m.CpuInfo[first:last+1])
+			}
+
+			r += fmt.Sprintf(
+				"%s %s",
+				standardRowStyle.SetString(label).String(),
+				cpuBar(usage, m.cpuBarWidth),
+			)
 		}
 
 		nRow := table.NewRow(table.RowData{
@@ -109,9 +415,57 @@ func generateCpuTableRows(m model) []table.Row {
 		rows = append(rows, nRow)
 	}
 
+	var legend []string
+	for _, segment := range cpuSegments {
+		legend = append(legend, segment.style.Render("█")+" "+standardRowStyle.Render(segment.name))
+	}
+	rows = append(rows, table.NewRow(table.RowData{
+		columnKeyCpuTable: strings.Join(legend, "  "),
+	}))
+
 	return rows
 }
 
+// cpuBar renders the usage of a CPU as a bar split in segments, one for the
+// time spent in each state, followed by the total percentage. The width
+// includes the percentage.
+func cpuBar(usage cpuUsage, width int) string {
+	percent := cpuPercentStyle.Render(fmt.Sprintf(cpuPercentFormat, min(usage.Total(), 100)))
+	barWidth := max(width-lipgloss.Width(percent), 0)
+
+	// Each segment ends where the accumulated usage does, so rounding doesn't
+	// make the bar longer than its width.
+	var bar strings.Builder
+	var accumulated float64
+	var filled int
+	for _, segment := range cpuSegments {
+		accumulated += segment.value(usage)
+		end := min(int(math.Round(accumulated/100*float64(barWidth))), barWidth)
+		if end > filled {
+			bar.WriteString(segment.style.Render(strings.Repeat("█", end-filled)))
+			filled = end
+		}
+	}
+	bar.WriteString(cpuEmptyStyle.Render(strings.Repeat("░", barWidth-filled)))
+
+	return bar.String() + percent
+}
+
+// formatMemoryUsage returns the used and total size of the memory.
+func formatMemoryUsage(info memoryInfo, units sizeUnits) string {
+	return units.format(info.Used) + "/" + units.format(info.Total)
+}
+
+// usageBar renders a percentage as a bar followed by its value. The width
+// includes the percentage.
+func usageBar(percent float64, width int) string {
+	label := fmt.Sprintf(" %3.0f%%", min(percent, 100))
+	barWidth := max(width-len(label), 0)
+	filled := min(int(math.Round(percent/100*float64(barWidth))), barWidth)
+
+	return strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled) + label
+}
+
 // newMemoryTable instantiates the RAM information table with its assigned
 // columns. This is called only when the application starts or it resizes.
 func newMemoryTable(m model) table.Model {
@@ -132,17 +486,22 @@ func newMemoryTable(m model) table.Model {
 // generateMemoryTableRows will generate all the rows that will be rendered into
 // the RAM information table. This is called each time the application updates.
 func generateMemoryTableRows(m model) []table.Row {
-	vMemoryProg := m.memoryProgresses[0].ViewAs(m.VMemoryInfo.UsedPercent / 100)
-	vMemoryView := fmt.Sprintf("%s %s", standardRowStyle.SetString(fmt.Sprintf("%.2f GB/%.2f GB", m.VMemoryInfo.Used, m.VMemoryInfo.Total)).String(), vMemoryProg)
+	vMemoryProg := memoryBar(m.VMemoryInfo, m.memoryBarWidth)
+	vMemoryView := fmt.Sprintf("%s %s", standardRowStyle.SetString(formatMemoryUsage(m.VMemoryInfo, m.opts.units)).String(), vMemoryProg)
 
-	sMemoryProg := m.memoryProgresses[1].ViewAs(m.SMemoryInfo.UsedPercent / 100)
-	sMemoryView := fmt.Sprintf("%s %s", standardRowStyle.SetString(fmt.Sprintf("%.2f GB/%.2f GB", m.SMemoryInfo.Used, m.SMemoryInfo.Total)).String(), sMemoryProg)
+	sMemoryProg := m.swapProgress.ViewAs(m.SMemoryInfo.UsedPercent / 100)
+	sMemoryView := fmt.Sprintf("%s %s", standardRowStyle.SetString(formatMemoryUsage(m.SMemoryInfo, m.opts.units)).String(), sMemoryProg)
 
 	rows := []table.Row{
 		table.NewRow(table.RowData{
 			columnKeyVirtualMemory: vMemoryView,
 			columnKeySwapMemory:    sMemoryView,
 		}),
+		// Names the segments of the virtual memory bar.
+		table.NewRow(table.RowData{
+			columnKeyVirtualMemory: memoryLegend(m.VMemoryInfo, m.opts.units, false),
+			columnKeySwapMemory:    "",
+		}),
 	}
 
 	return rows
@@ -151,22 +510,70 @@ func generateMemoryTableRows(m model) []table.Row {
 // newDisksTable instantiates the disks information table with its assigned
 // columns. This is called only when the application starts or it resizes.
 func newDisksTable(m model) table.Model {
-	fsTypeCol := table.NewFlexColumn("FsType", "File System Type", columnDefaultFlexFactor)
-	deviceCol := table.NewFlexColumn("Device", "Device", columnDefaultFlexFactor)
-	mountPathCol := table.NewFlexColumn("MountPath", "Mount Path", columnHugeFlexFactor)
-	totalSizeCol := table.NewFlexColumn("TotalSize", "Total Size", columnDefaultFlexFactor)
-	freeSizeCol := table.NewFlexColumn("FreeSize", "Free Size", columnDefaultFlexFactor)
-	usedSizeCol := table.NewFlexColumn("UsedSize", "Used Size", columnDefaultFlexFactor)
-
-	columns := []table.Column{fsTypeCol, deviceCol, mountPathCol, totalSizeCol, freeSizeCol, usedSizeCol}
-
-	return table.
-		New(columns).
+	t := table.
+		New(disksTableColumns(m)).
 		BorderRounded().
 		WithBaseStyle(styleBase.Copy().Align(lipgloss.Left)).
-		WithTargetWidth(m.Width).
-		SortByAsc("FsType").
-		ThenSortByAsc("MountPath")
+		WithTargetWidth(disksTableWidth(m))
+
+	return sortDisksTable(t, m)
+}
+
+// disksTableWidth returns the width of the disks table, which shares its
+// row with the network table.
+func disksTableWidth(m model) int {
+	return int(float64(m.Width) * disksTableWidthRatio)
+}
+
+// disksTableColumns returns the columns of the disks table, marking the one
+// the disks are sorted by.
+func disksTableColumns(m model) []table.Column {
+	col := func(key, title string, flexFactor int) table.Column {
+		return table.NewFlexColumn(key, sortedTitle(key, title, m.disksSortKey, m.disksSortDesc), flexFactor)
+	}
+
+	fsTypeCol := col("FsType", "File System Type", columnDefaultFlexFactor)
+	deviceCol := col("Device", "Device", columnDefaultFlexFactor)
+	mountPathCol := col("MountPath", "Mount Path", columnHugeFlexFactor)
+	totalSizeCol := col("TotalSize", "Total Size", columnLargerFlexFactor)
+	freeSizeCol := col("FreeSize", "Free Size", columnLargerFlexFactor)
+	usedSizeCol := col("UsedSize", "Used Size", columnLargerFlexFactor)
+	// The bar has a fixed width so it is never cut.
+	usedPercentCol := table.NewColumn("UsedPercent",
+		sortedTitle("UsedPercent", "Use%", m.disksSortKey, m.disksSortDesc), diskUsageBarWidth)
+	inodesCol := col("InodesTotal", "Inodes", columnDefaultFlexFactor)
+	inodesUsedCol := col("InodesUsed", "IUsed", columnDefaultFlexFactor)
+	inodesFreeCol := col("InodesFree", "IFree", columnDefaultFlexFactor)
+	// Activity of the device holding the file system.
+	readCol := col("ReadRate", "Read/s", columnDefaultFlexFactor)
+	writeCol := col("WriteRate", "Write/s", columnDefaultFlexFactor)
+	iopsCol := col("IOPS", "IOPS", columnDefaultFlexFactor).WithFormatString("%.0f")
+	utilCol := col("Utilization", "%util", columnDefaultFlexFactor).WithFormatString("%.1f%%")
+	awaitCol := col("Await", "await", columnDefaultFlexFactor).WithFormatString("%.1f ms")
+
+	return []table.Column{fsTypeCol, deviceCol, mountPathCol, totalSizeCol, freeSizeCol, usedSizeCol,
+		usedPercentCol, inodesCol, inodesUsedCol, inodesFreeCol, readCol, writeCol, iopsCol, utilCol, awaitCol}
+}
+
+// sortDisksTable applies the sort chosen by the user to the given disks
+// table.
+func sortDisksTable(t table.Model, m model) table.Model {
+	// Rows are sorted when generated, see generateDisksTableRows.
+	return t.WithColumns(disksTableColumns(m)).WithRows(generateDisksTableRows(m))
+}
+
+// sortedTitle appends an arrow showing the sort order to the title of the
+// column the table is sorted by.
+func sortedTitle(key, title, sortKey string, desc bool) string {
+	if key != sortKey {
+		return title
+	}
+
+	if desc {
+		return title + sortDescArrow
+	}
+
+	return title + sortAscArrow
 }
 
 // generateDisksTableRows will generate all the rows that will be rendered into
@@ -174,15 +581,36 @@ func newDisksTable(m model) table.Model {
 func generateDisksTableRows(m model) []table.Row {
 	var rows []table.Row
 
-	for _, disk := range m.DisksInfo {
+	disks := make([]diskInfo, len(m.DisksInfo))
+	copy(disks, m.DisksInfo)
+	sortDisks(disks, m.disksSortKey, m.disksSortDesc)
+
+	for _, disk := range disks {
 		rowData := make(table.RowData)
 
 		rowData["FsType"] = disk.FsType
 		rowData["Device"] = disk.Device
 		rowData["MountPath"] = disk.MountPath
-		rowData["TotalSize"] = fmt.Sprintf("%2.f GB", disk.TotalSize)
-		rowData["FreeSize"] = fmt.Sprintf("%2.f GB", disk.FreeSize)
-		rowData["UsedSize"] = fmt.Sprintf("%2.f GB", disk.UsedSize)
+		rowData["TotalSize"] = m.opts.units.format(disk.TotalSize)
+		rowData["FreeSize"] = m.opts.units.format(disk.FreeSize)
+		rowData["UsedSize"] = m.opts.units.format(disk.UsedSize)
+		rowData["UsedPercent"] = usageBar(disk.UsedPercent, diskUsageBarWidth)
+		// An almost full file system is highlighted.
+		if disk.UsedPercent >= fullDiskPercent {
+			rowData["UsedPercent"] = table.NewStyledCell(rowData["UsedPercent"], alertStyle)
+		}
+		rowData["InodesTotal"] = formatInodes(disk, disk.InodesTotal)
+		rowData["InodesUsed"] = formatInodes(disk, disk.InodesUsed)
+		rowData["InodesFree"] = formatInodes(disk, disk.InodesFree)
+		rowData["ReadRate"] = m.opts.units.formatRate(disk.ReadRate)
+		rowData["WriteRate"] = m.opts.units.formatRate(disk.WriteRate)
+		rowData["IOPS"] = disk.IOPS
+		rowData["Utilization"] = disk.Utilization
+		// A saturated device is highlighted.
+		if disk.Utilization >= saturatedUtilization {
+			rowData["Utilization"] = table.NewStyledCell(disk.Utilization, alertStyle)
+		}
+		rowData["Await"] = disk.Await
 
 		row := table.NewRow(rowData).WithStyle(standardRowStyle)
 		rows = append(rows, row)
@@ -191,59 +619,362 @@ func generateDisksTableRows(m model) []table.Row {
 	return rows
 }
 
-// newProcessesTable instantiates the processes information table with its
-// assigned columns. This is called only when the application starts or it
-// resizes.
-func newProcessesTable(m model, pCount int) table.Model {
-	pIdCol := table.NewFlexColumn("PId", "Process ID", columnDefaultFlexFactor)
-	prioCol := table.NewFlexColumn("Priority", "Priority", columnDefaultFlexFactor)
+// newNetworkTable instantiates the network interfaces table with its assigned
+// columns. It is shown next to the disks table, taking the rest of the width.
+// This is called only when the application starts or it resizes.
+func newNetworkTable(m model) table.Model {
+	columns := []table.Column{
+		table.NewFlexColumn("Interface", "Interface", columnLargerFlexFactor),
+		table.NewFlexColumn("RecvRate", "RX/s", columnLargerFlexFactor),
+		table.NewFlexColumn("SentRate", "TX/s", columnLargerFlexFactor),
+		table.NewFlexColumn("PacketsRecv", "RX pkt/s", columnLargerFlexFactor).WithFormatString("%.0f"),
+		table.NewFlexColumn("PacketsSent", "TX pkt/s", columnLargerFlexFactor).WithFormatString("%.0f"),
+		table.NewFlexColumn("Errors", "Err/s", columnLargerFlexFactor).WithFormatString("%.0f"),
+		table.NewFlexColumn("Drops", "Drop/s", columnLargerFlexFactor).WithFormatString("%.0f"),
+	}
 
-	uCol := table.NewFlexColumn("User", "Username", columnLargerFlexFactor)
-	cPcgCol := table.NewFlexColumn("CpuPercentage", "CPU Usage Percentage", columnLargerFlexFactor).WithFormatString("%.1f%%")
-	nCol := table.NewFlexColumn("Name", "Name", columnLargerFlexFactor)
+	return table.
+		New(columns).
+		BorderRounded().
+		WithBaseStyle(styleBase.Copy().Align(lipgloss.Left)).
+		WithTargetWidth(m.Width - disksTableWidth(m)).
+		SortByAsc("Interface")
+}
 
-	exePCol := table.NewFlexColumn("ExeP", "Executable Path", columnHugeFlexFactor)
+// generateNetworkTableRows will generate all the rows that will be rendered
+// into the network interfaces table. The loopback and virtual interfaces are
+// left out unless the user chose to show them. This is called each time the
+// application updates.
+func generateNetworkTableRows(m model) []table.Row {
+	var rows []table.Row
 
-	cmdlineCol := table.NewFlexColumn("Cmdline", "Command", columnLargestFlexFactor)
+	for _, network := range m.NetworkInfo {
+		if !m.showVirtualInterfaces && isVirtualInterface(network.Interface) {
+			continue
+		}
 
-	columns := []table.Column{pIdCol, prioCol, uCol, cPcgCol, nCol, exePCol, cmdlineCol}
+		rowData := make(table.RowData)
 
-	// Not showing the exePCol as name and executable path are the same in darwin
-	// based systems.
-	if runtime.GOOS == "darwin" {
-		columns = []table.Column{pIdCol, prioCol, uCol, cPcgCol, nCol, cmdlineCol}
+		rowData["Interface"] = network.Interface
+		rowData["RecvRate"] = m.opts.units.formatRate(network.RecvRate)
+		rowData["SentRate"] = m.opts.units.formatRate(network.SentRate)
+		rowData["PacketsRecv"] = network.PacketsRecv
+		rowData["PacketsSent"] = network.PacketsSent
+		rowData["Errors"] = network.Errors
+		rowData["Drops"] = network.Drops
+		if network.Errors > 0 {
+			rowData["Errors"] = table.NewStyledCell(network.Errors, alertStyle)
+		}
+		if network.Drops > 0 {
+			rowData["Drops"] = table.NewStyledCell(network.Drops, alertStyle)
+		}
+
+		row := table.NewRow(rowData).WithStyle(standardRowStyle)
+		rows = append(rows, row)
 	}
 
+	return rows
+}
+
+// newProcessesTable instantiates the processes information table with its
+// assigned columns. This is called only when the application starts or it
+// resizes.
+func newProcessesTable(m model, pCount int) table.Model {
+	// Rows are sorted when generated, see generateProcessesTableRows.
 	return table.
-		New(columns).
+		New(processesTableColumns(m)).
 		BorderRounded().
 		WithBaseStyle(styleBase.Copy().Align(lipgloss.Left)).
 		WithTargetWidth(m.Width).
 		WithPageSize(pCount).
-		SortByDesc("CpuPercentage").
+		WithKeyMap(processesTableKeyMap()).
+		WithRows(generateProcessesTableRows(m)).
 		Focused(true)
 }
 
+// processesTableColumns returns the columns of the processes table, marking
+// the one the processes are sorted by.
+func processesTableColumns(m model) []table.Column {
+	col := func(key, title string, flexFactor int) table.Column {
+		return table.NewFlexColumn(key, sortedTitle(key, title, m.sortKey, m.sortDesc), flexFactor)
+	}
+
+	pIdCol := col("PId", "Process ID", columnDefaultFlexFactor)
+	prioCol := col("Priority", "Priority", columnDefaultFlexFactor)
+	niceCol := col("Nice", "NI", columnDefaultFlexFactor)
+	stateCol := col("State", "S", columnDefaultFlexFactor)
+	threadsCol := col("Threads", "NLWP", columnDefaultFlexFactor)
+	startCol := col("StartTime", "START", columnDefaultFlexFactor)
+	cpuTimeCol := col("CpuTime", "TIME+", columnDefaultFlexFactor)
+	ioReadCol := col("IOReadRate", "IO_R", columnDefaultFlexFactor)
+	ioWriteCol := col("IOWriteRate", "IO_W", columnDefaultFlexFactor)
+
+	uCol := col("User", "Username", columnLargerFlexFactor)
+	cPcgCol := col("CpuPercentage", "CPU Usage Percentage", columnLargerFlexFactor).WithFormatString("%.1f%%")
+	mPcgCol := col("MemPercentage", "MEM%", columnDefaultFlexFactor).WithFormatString("%.1f%%")
+	resCol := col("Resident", "RES", columnDefaultFlexFactor)
+	virtCol := col("Virtual", "VIRT", columnDefaultFlexFactor)
+	shrCol := col("Shared", "SHR", columnDefaultFlexFactor)
+	nCol := col("Name", "Name", columnLargerFlexFactor)
+	containerCol := col("Container", "Container", columnLargerFlexFactor)
+
+	exePCol := col("ExeP", "Executable Path", columnHugeFlexFactor)
+
+	cmdlineCol := col("Cmdline", "Command", columnLargestFlexFactor)
+
+	columns := []table.Column{pIdCol, prioCol, niceCol, uCol, stateCol, threadsCol, cPcgCol, mPcgCol,
+		resCol, virtCol, shrCol, ioReadCol, ioWriteCol, startCol, cpuTimeCol, containerCol, nCol, exePCol, cmdlineCol}
+
+	// Not showing the exePCol as name and executable path are the same in darwin
+	// based systems. The shared memory, the threads, the I/O and the cgroups
+	// aren't reported by its ps command either.
+	if runtime.GOOS == "darwin" {
+		columns = []table.Column{pIdCol, prioCol, niceCol, uCol, stateCol, cPcgCol, mPcgCol,
+			resCol, virtCol, startCol, cpuTimeCol, nCol, cmdlineCol}
+	}
+
+	return columns
+}
+
+// processesTableKeyMap returns the keys used for navigating the processes
+// table. Keys used for acting on the processes are removed from the defaults,
+// like k for sending signals or space for tagging.
+func processesTableKeyMap() table.KeyMap {
+	keys := table.DefaultKeyMap()
+	keys.RowUp = key.NewBinding(key.WithKeys("up"))
+	keys.RowSelectToggle = key.NewBinding(key.WithDisabled())
+	keys.Filter = key.NewBinding(key.WithDisabled())
+
+	return keys
+}
+
 // generateProcessesTableRows will generate all the rows that will be rendered
 // into the processes information table. This is called each time the
 // application updates.
 func generateProcessesTableRows(m model) []table.Row {
 	var rows []table.Row
 
+	var processes []processInfo
 	for _, process := range m.Processes {
+		if m.filter.match(process) {
+			processes = append(processes, process)
+		}
+	}
+
+	// In the tree view the name of each process is preceded by its branches.
+	var treeRows []processTreeRow
+	var groups []processGroup
+	if m.groupView {
+		groups = groupProcesses(processes, m.sortKey, m.sortDesc)
+		processes = processes[:0]
+		for _, group := range groups {
+			processes = append(processes, group.process)
+		}
+	} else if m.treeView {
+		treeRows = processTree(processes, m.sortKey, m.sortDesc, m.collapsed)
+		processes = processes[:0]
+		for _, treeRow := range treeRows {
+			processes = append(processes, treeRow.process)
+		}
+	} else {
+		sortProcesses(processes, m.sortKey, m.sortDesc)
+	}
+
+	for i, process := range processes {
 		rowData := make(table.RowData)
 
 		rowData["PId"] = process.PId
 		rowData["Priority"] = process.Priority
 		rowData["User"] = process.User
 		rowData["CpuPercentage"] = process.CpuPercentage
+		rowData["MemPercentage"] = process.MemPercentage
+		rowData["Resident"] = m.opts.units.format(process.Resident)
+		rowData["Virtual"] = m.opts.units.format(process.Virtual)
+		rowData["Shared"] = m.opts.units.format(process.Shared)
+		rowData["Nice"] = process.Nice
+		rowData["State"] = process.State
+		// Zombie and uninterruptible sleep processes are highlighted.
+		if process.State == "Z" || process.State == "D" {
+			rowData["State"] = table.NewStyledCell(process.State, alertStyle)
+		}
+		rowData["Threads"] = process.Threads
+		rowData["StartTime"] = formatStartTime(process.StartTime)
+		rowData["CpuTime"] = formatCpuTime(process.CpuTime)
+		rowData["IOReadRate"] = formatIORate(process, process.IOReadRate, m.opts.units)
+		rowData["IOWriteRate"] = formatIORate(process, process.IOWriteRate, m.opts.units)
 		rowData["Name"] = process.Name
+		if m.treeView {
+			rowData["Name"] = treeRows[i].prefix + process.Name
+			if treeRows[i].collapsed {
+				rowData["Name"] = treeRows[i].prefix + "+" + process.Name
+			}
+		}
 		rowData["ExeP"] = process.ExeP
 		rowData["Cmdline"] = process.Cmdline
+		rowData["Container"] = process.Container
+		// The rows of the groups have no process, so they can't be acted on.
+		if m.groupView {
+			for _, key := range []string{"PId", "Priority", "Nice", "State", "ExeP"} {
+				rowData[key] = ""
+			}
+			rowData["Name"] = fmt.Sprintf("%d processes", groups[i].count)
+			rowData["Cmdline"] = process.Cgroup
+		}
 
 		row := table.NewRow(rowData).WithStyle(standardRowStyle)
+		if _, ok := m.tagged[process.PId]; ok {
+			row = row.WithStyle(taggedRowStyle)
+		}
 		rows = append(rows, row)
 	}
 
 	return rows
 }
+
+// toggleTreeView switches the processes table between the flat list and the
+// tree of processes.
+func (m model) toggleTreeView() model {
+	m.treeView = !m.treeView
+	m.groupView = false
+	m.processesTable = m.processesTable.
+		WithRows(generateProcessesTableRows(m)).
+		WithHighlightedRow(0)
+
+	return m
+}
+
+// toggleGroupView switches the processes table between the flat list and the
+// groups of processes by container.
+func (m model) toggleGroupView() model {
+	m.groupView = !m.groupView
+	m.treeView = false
+	m.processesTable = m.processesTable.
+		WithRows(generateProcessesTableRows(m)).
+		WithHighlightedRow(0)
+
+	return m
+}
+
+// foldProcess collapses or expands the subtree of the highlighted process
+// in the tree view.
+func (m model) foldProcess(collapse bool) model {
+	if !m.treeView || len(m.processesTable.GetVisibleRows()) == 0 {
+		return m
+	}
+
+	pId, ok := m.processesTable.HighlightedRow().Data["PId"].(int32)
+	if !ok {
+		return m
+	}
+
+	if collapse {
+		m.collapsed[pId] = struct{}{}
+	} else {
+		delete(m.collapsed, pId)
+	}
+
+	// The highlighted process keeps its position as its descendants are
+	// listed after it.
+	m.processesTable = m.processesTable.WithRows(generateProcessesTableRows(m))
+
+	return m
+}
+
+// sizeUnits are the multiples the sizes are shown with.
+type sizeUnits int
+
+const (
+	// Powers of 1024: KiB, MiB, GiB, etc.
+	iecUnits sizeUnits = iota
+	// Powers of 1000: kB, MB, GB, etc.
+	siUnits
+)
+
+// sizeUnitNames are the names of the multiples of each system of units, from
+// the smallest.
+var sizeUnitNames = map[sizeUnits][]string{
+	iecUnits: {"B", "KiB", "MiB", "GiB", "TiB"},
+	siUnits:  {"B", "kB", "MB", "GB", "TB"},
+}
+
+// format returns the given amount of bytes using the largest unit that keeps
+// the value above one.
+func (u sizeUnits) format(bytes uint64) string {
+	base := 1024.0
+	if u == siUnits {
+		base = 1000
+	}
+
+	names := sizeUnitNames[u]
+	value := float64(bytes)
+	unit := 0
+	for value >= base && unit < len(names)-1 {
+		value /= base
+		unit++
+	}
+
+	if unit == 0 {
+		return fmt.Sprintf("%d %s", bytes, names[unit])
+	}
+
+	return fmt.Sprintf("%.1f %s", value, names[unit])
+}
+
+// formatRate returns the given amount of bytes per second.
+func (u sizeUnits) formatRate(rate float64) string {
+	return u.format(uint64(rate)) + "/s"
+}
+
+// formatInodes returns the given amount of inodes of the file system in a
+// short form, or a placeholder when the file system doesn't report inodes,
+// like vfat or btrfs.
+func formatInodes(d diskInfo, inodes uint64) string {
+	switch {
+	case d.InodesTotal == 0:
+		return "N/A"
+	case inodes >= 1e9:
+		return fmt.Sprintf("%.1fG", float64(inodes)/1e9)
+	case inodes >= 1e6:
+		return fmt.Sprintf("%.1fM", float64(inodes)/1e6)
+	case inodes >= 1e3:
+		return fmt.Sprintf("%.1fk", float64(inodes)/1e3)
+	default:
+		return strconv.FormatUint(inodes, 10)
+	}
+}
+
+// formatIORate returns the given I/O rate of the process, or a placeholder
+// when its I/O can't be read.
+func formatIORate(p processInfo, rate float64, units sizeUnits) string {
+	if !p.IOKnown {
+		return "N/A"
+	}
+
+	return units.formatRate(rate)
+}
+
+// formatStartTime returns the hour and minute a process started if it did in
+// the last 24 hours, otherwise the month and day, like htop does.
+func formatStartTime(t time.Time) string {
+	if time.Since(t) < 24*time.Hour {
+		return t.Format("15:04")
+	}
+
+	return t.Format("Jan02")
+}
+
+// formatCpuTime returns the CPU time of a process as minutes:seconds.hundredths,
+// or as hours followed by minutes:seconds when it reaches an hour.
+func formatCpuTime(d time.Duration) string {
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	seconds := int(d.Seconds()) % 60
+
+	if hours > 0 {
+		return fmt.Sprintf("%dh%02d:%02d", hours, minutes, seconds)
+	}
+
+	hundredths := int(d.Milliseconds()/10) % 100
+
+	return fmt.Sprintf("%d:%02d.%02d", minutes, seconds, hundredths)
+}
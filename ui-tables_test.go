@@ -0,0 +1,119 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+func TestCpuTableLayout(t *testing.T) {
+	tests := []struct {
+		cores, width                  int
+		columns, group, progressWidth int
+	}{
+		{cores: 1, width: 20, columns: 1, group: 1, progressWidth: 7},
+		{cores: 1, width: 80, columns: 1, group: 1, progressWidth: 15},
+		// Up to 8 rows fit in a single column.
+		{cores: 6, width: 20, columns: 1, group: 1, progressWidth: 7},
+		{cores: 6, width: 60, columns: 2, group: 1, progressWidth: 15},
+		{cores: 6, width: 80, columns: 3, group: 1, progressWidth: 15},
+		{cores: 6, width: 200, columns: 3, group: 1, progressWidth: 30},
+		// Too many cores for the width, so each bar shows several of them.
+		{cores: 256, width: 20, columns: 1, group: 32, progressWidth: 2},
+		{cores: 256, width: 40, columns: 1, group: 32, progressWidth: 15},
+		{cores: 256, width: 80, columns: 2, group: 16, progressWidth: 15},
+		{cores: 1000, width: 80, columns: 2, group: 63, progressWidth: 15},
+	}
+
+	for _, tt := range tests {
+		columns, group, progressWidth := cpuTableLayout(tt.cores, tt.width)
+		if columns != tt.columns || group != tt.group || progressWidth != tt.progressWidth {
+			t.Errorf("cpuTableLayout(%d, %d) = %d, %d, %d, want %d, %d, %d", tt.cores, tt.width,
+				columns, group, progressWidth, tt.columns, tt.group, tt.progressWidth)
+		}
+
+		// Every core is shown without the table getting taller than the
+		// heights of the window count on.
+		bars := ceilDiv(tt.cores, group)
+		if rows := ceilDiv(bars, columns); rows > cpuTableMaxRowAmount {
+			t.Errorf("cpuTableLayout(%d, %d) takes %d rows, want at most %d", tt.cores, tt.width, rows, cpuTableMaxRowAmount)
+		}
+	}
+}
+
+func TestDiskFilterMatch(t *testing.T) {
+	root := disk.PartitionStat{Device: "/dev/nvme0n1p2", Mountpoint: "/", Fstype: "ext4"}
+	home := disk.PartitionStat{Device: "/dev/nvme0n1p3", Mountpoint: "/home", Fstype: "btrfs"}
+	usb := disk.PartitionStat{Device: "/dev/sdb1", Mountpoint: "/media/usb", Fstype: "vfat"}
+	tmp := disk.PartitionStat{Device: "tmpfs", Mountpoint: "/tmp", Fstype: "tmpfs"}
+
+	tests := []struct {
+		name                        string
+		types, excludeTypes         string
+		device, excludeDevice       string
+		mountPath, excludeMountPath string
+		// Whether root, home, usb and tmp are shown, in that order.
+		want [4]bool
+	}{
+		{name: "listed types", types: "ext4, btrfs", want: [4]bool{true, true, false, false}},
+		{name: "every type", types: "all", want: [4]bool{true, true, true, true}},
+		{name: "excluded type", types: "all", excludeTypes: "tmpfs,vfat", want: [4]bool{true, true, false, false}},
+		{name: "excluded over listed", types: "ext4,btrfs", excludeTypes: "btrfs", want: [4]bool{true, false, false, false}},
+		{name: "device", types: "all", device: "^/dev/nvme", want: [4]bool{true, true, false, false}},
+		{name: "excluded device", types: "all", excludeDevice: "p3$", want: [4]bool{true, false, true, true}},
+		{name: "mount path", types: "all", mountPath: "^/(home|tmp)$", want: [4]bool{false, true, false, true}},
+		{name: "excluded mount path", types: "all", excludeMountPath: "^/media/", want: [4]bool{true, true, false, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := newDiskFilter(tt.types, tt.excludeTypes, tt.device, tt.excludeDevice, tt.mountPath, tt.excludeMountPath)
+			if err != nil {
+				t.Fatalf("newDiskFilter: %v", err)
+			}
+
+			for i, p := range []disk.PartitionStat{root, home, usb, tmp} {
+				if got := filter.match(p); got != tt.want[i] {
+					t.Errorf("match(%s) = %v, want %v", p.Mountpoint, got, tt.want[i])
+				}
+			}
+		})
+	}
+
+	if _, err := newDiskFilter("all", "", "(", "", "", ""); err == nil {
+		t.Error("newDiskFilter succeeded with an invalid pattern, want an error")
+	}
+}
+
+func TestSizeUnitsFormat(t *testing.T) {
+	tests := []struct {
+		units sizeUnits
+		bytes uint64
+		want  string
+	}{
+		{iecUnits, 0, "0 B"},
+		{iecUnits, 1023, "1023 B"},
+		{iecUnits, 1024, "1.0 KiB"},
+		{iecUnits, 1536, "1.5 KiB"},
+		{iecUnits, 1024 * 1024, "1.0 MiB"},
+		{iecUnits, 5 * 1024 * 1024 * 1024, "5.0 GiB"},
+		// Past the largest unit the value keeps growing.
+		{iecUnits, 2048 * 1024 * 1024 * 1024 * 1024, "2048.0 TiB"},
+		{siUnits, 999, "999 B"},
+		{siUnits, 1000, "1.0 kB"},
+		{siUnits, 1023, "1.0 kB"},
+		{siUnits, 1024, "1.0 kB"},
+		{siUnits, 1500000, "1.5 MB"},
+		{siUnits, 1e12, "1.0 TB"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.units.format(tt.bytes); got != tt.want {
+			t.Errorf("format(%d) = %q, want %q", tt.bytes, got, tt.want)
+		}
+	}
+
+	if got := siUnits.formatRate(2500); got != "2.5 kB/s" {
+		t.Errorf("formatRate(2500) = %q, want \"2.5 kB/s\"", got)
+	}
+}
@@ -2,9 +2,12 @@
 package main
 
 import (
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/evertras/bubble-table/table"
@@ -14,32 +17,148 @@ const (
 	interval time.Duration = time.Second
 
 	// Minimum terminal's window height for showing one tables.
-	minimumHeightOneTable = 9
-	// Minimum terminal's window height for showing two tables.
-	minimumHeightTwoTables = 14
+	minimumHeightOneTable = 11
+	// Minimum terminal's window height for showing two tables. From this
+	// height on the header is shown above the CPU table.
+	minimumHeightTwoTables = 17 + headerTableHeight
 	// Minimum terminal's window height for showing three tables.
-	minimumHeightThreeTables = 24
+	minimumHeightThreeTables = 27 + headerTableHeight
 	// Minimum terminal's window height for showing all tables.
-	minimumHeightAllTables = 33
+	minimumHeightAllTables = 36 + headerTableHeight
+
+	// Lines taken by the header: its three rows and borders.
+	headerTableHeight = 5
+)
+
+// screenKind is what is shown below the header.
+type screenKind int
+
+const (
+	screenTables screenKind = iota
+	// History of the usage, see graphsView.
+	screenGraphs
+	// Breakdown of the memory, see memoryDetailsView.
+	screenMemory
+	// Usage and limits of each cgroup, see cgroupsView.
+	screenCgroups
+	// Details of a single process, see processDetailsContent.
+	screenProcess
+	// Files open by the process of the detail view, see filesView.
+	screenFiles
+	// Memory mappings of the process of the detail view, see mapsView.
+	screenMaps
 )
 
 type model struct {
-	CpuInfo []float64
+	// Settings given through the command line.
+	opts options
+
+	// Usage of the whole CPU.
+	CpuAll cpuUsage
+	// Usage of each core.
+	CpuInfo []cpuUsage
 	// Virtual Memory.
 	VMemoryInfo memoryInfo
 	// Swap Memory.
 	SMemoryInfo memoryInfo
 	Processes   []processInfo
 	DisksInfo   []diskInfo
-
-	cpuProgresses    []progress.Model
-	memoryProgresses []progress.Model
-
+	SystemInfo  systemInfo
+	NetworkInfo []networkInfo
+	// Memory accounting of the kernel, only read while it is shown.
+	MemoryDetails memoryDetails
+	// Usage and limits of each cgroup, only read while they are shown, and
+	// why they couldn't be read.
+	Cgroups         []cgroupInfo
+	cgroupsErr      error
+	cgroupCollector *cgroupCollector
+	// Past usage of the CPU, the memory and the disks.
+	history *history
+
+	// Amount of columns the cores are laid out in, the amount of cores each
+	// usage bar shows and the width of the bars.
+	cpuColumns  int
+	cpuGroup    int
+	cpuBarWidth int
+	// The virtual memory bar is split by the parts of the memory in use, the
+	// swap one is a progress bar as wide.
+	memoryBarWidth int
+	swapProgress   progress.Model
+
+	headerTable    table.Model
 	cpuTable       table.Model
 	memoryTable    table.Model
 	disksTable     table.Model
+	networkTable   table.Model
+	cgroupsTable   table.Model
+	filesTable     table.Model
+	mapsTable      table.Model
 	processesTable table.Model
 
+	// Processes tagged by the user to act on them as a batch.
+	tagged map[int32]struct{}
+	// Lists the loopback and virtual network interfaces too.
+	showVirtualInterfaces bool
+	// Shows the graphs of the past usage, the memory details, the cgroups or
+	// the details of a process, its open files or its memory maps in place of
+	// the tables.
+	screen screenKind
+	// Process shown in the detail view, its details and why they couldn't be
+	// read the last time.
+	detailPId         int32
+	ProcessDetails    processDetails
+	processDetailsErr error
+	detailViewport    viewport.Model
+	// Files open by the process of the detail view, only read while they are
+	// shown, why they couldn't be read and the text they are filtered by.
+	OpenFiles    []openFile
+	openFilesErr error
+	filesFilter  string
+	// Memory mappings of the process of the detail view, only read while
+	// they are shown, and why they couldn't be read. They are listed by file
+	// instead of by mapping when mapsByFile is set.
+	MemoryMaps    memoryMaps
+	memoryMapsErr error
+	mapsByFile    bool
+	// Shows the processes as a tree built from their parents.
+	treeView bool
+	// Shows a row for each container, pod or systemd unit, adding up the
+	// usage of its processes, instead of a row for each process.
+	groupView bool
+	// Processes whose subtree is folded in the tree view.
+	collapsed map[int32]struct{}
+	// Dialog shown in place of the processes table.
+	dialog dialogKind
+	// Processes the action of the dialog applies to.
+	dialogTargets []int32
+	signalMenu    menu
+	niceInput     textinput.Model
+	ioClassMenu   menu
+	ioLevelMenu   menu
+	queryInput    textinput.Model
+	sortMenu      menu
+	// Column keys of the sort menu's options.
+	sortMenuKeys []string
+	// Row highlighted when the search started, the matches are looked for
+	// from it while the query is typed.
+	searchOrigin int
+	// Last query the processes were searched by.
+	search processFilter
+	// Query the listed processes must match. It is kept between updates.
+	filter processFilter
+
+	// Columns the tables are sorted by, kept when the tables are rebuilt.
+	sortKey       string
+	sortDesc      bool
+	disksSortKey  string
+	disksSortDesc bool
+	mapsSortKey   string
+	mapsSortDesc  bool
+
+	// Result of the last action taken by the user.
+	status        string
+	statusIsError bool
+
 	// Window's width.
 	Width int
 	// Window's height.
@@ -47,30 +166,32 @@ type model struct {
 }
 
 // NewModel initializes the model that BubbleTea will use.
-func NewModel() model {
-	// Initial model instance with CpuInfo filled.
+func NewModel(o options) model {
+	// Initial model instance. CpuInfo is filled right after as it sizes the
+	// CPU table.
 	teaModel := model{
-		CpuInfo: getCpuInfo(),
+		opts:      o,
+		history:   newHistory(int(o.history / interval)),
+		tagged:    make(map[int32]struct{}),
+		collapsed: make(map[int32]struct{}),
+
+		cgroupCollector: newCgroupCollector(o.cgroupRoot),
+
+		sortKey:      defaultProcessesSortKey,
+		sortDesc:     true,
+		disksSortKey: defaultDisksSortKey,
+		mapsSortKey:  defaultMapsSortKey,
+		mapsSortDesc: true,
 	}
+	teaModel.CpuAll, teaModel.CpuInfo = getCpuInfo()
 
-	// Creating progress bars for the Cpu and Memory tables.
-	opts := []progress.Option{
+	// Creating the progress bar of the swap memory. The CPU and the virtual
+	// memory draw their own bars as they are split in segments.
+	teaModel.swapProgress = progress.New(
 		progress.WithDefaultGradient(),
 		progress.WithSolidFill("#207883"),
-	}
-	for range teaModel.CpuInfo {
-		pBar := progress.New(opts...)
-		pBar.PercentFormat = " %05.2f%% "
-		pBar.PercentageStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#EFFAFB"))
-
-		teaModel.cpuProgresses = append(teaModel.cpuProgresses, pBar)
-	}
-
-	opts = append(opts, progress.WithoutPercentage())
-	teaModel.memoryProgresses = []progress.Model{
-		progress.New(opts...), // One for each type of memory.
-		progress.New(opts...),
-	}
+		progress.WithoutPercentage(),
+	)
 
 	return teaModel
 }
@@ -100,16 +221,127 @@ func (model) Init() tea.Cmd {
 // * Terminal's window resizing.
 // * "Tick"s, where another one is returned and hence creating a loop.
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// While a dialog is shown it receives all the keys pressed.
+	if msg, ok := msg.(tea.KeyMsg); ok && m.dialog != dialogNone {
+		return m.updateDialog(msg)
+	}
+
+	// The open files table takes every key but the ones leaving it.
+	if msg, ok := msg.(tea.KeyMsg); ok && m.screen == screenFiles {
+		switch msg.String() {
+		case "esc", "o":
+			m.screen = screenProcess
+			return m, nil
+		case "\\", "f4":
+			return m.openFilesFilterPrompt()
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		}
+
+		var cmd tea.Cmd
+		m.filesTable, cmd = m.filesTable.Update(msg)
+		return m, cmd
+	}
+
+	// The memory maps table takes every key but the ones leaving it.
+	if msg, ok := msg.(tea.KeyMsg); ok && m.screen == screenMaps {
+		switch msg.String() {
+		case "esc", "M":
+			m.screen = screenProcess
+			return m, nil
+		case "s":
+			return m.cycleMapsSort(), nil
+		case "S":
+			m.mapsSortDesc = !m.mapsSortDesc
+			return m.sortMapsTable(), nil
+		case "f":
+			return m.toggleMapsByFile(), nil
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		}
+
+		var cmd tea.Cmd
+		m.mapsTable, cmd = m.mapsTable.Update(msg)
+		return m, cmd
+	}
+
 	// Given a keyword pressed return the updated model and a command.
 	if msg, ok := msg.(tea.KeyMsg); ok {
-		if k := msg.String(); k == "q" || k == "esc" || k == "ctrl+c" {
+		// Esc goes back to the tables from every other screen, and only quits
+		// from the tables.
+		if k := msg.String(); k == "esc" && m.screen != screenTables || k == "enter" && m.screen == screenProcess {
+			m.screen = screenTables
+			return m, nil
+		} else if k == "o" && m.screen == screenProcess {
+			return m.openOpenFiles(), nil
+		} else if k == "M" && m.screen == screenProcess {
+			return m.openMemoryMaps(), nil
+		} else if k == "q" || k == "esc" || k == "ctrl+c" {
 			return m, tea.Quit
+		} else if k == "enter" && m.screen == screenTables {
+			return m.openProcessDetails(), nil
 		} else if k == "a" || k == "A" {
 			m.disksTable = m.disksTable.PageUp()
+			m.networkTable = m.networkTable.PageUp()
 			return m, nil
 		} else if k == "d" || k == "D" {
 			m.disksTable = m.disksTable.PageDown()
+			m.networkTable = m.networkTable.PageDown()
+			return m, nil
+		} else if (k == "f9" || k == "k") && m.processActionsShown() {
+			return m.openSignalMenu(), nil
+		} else if k == "f7" && m.processActionsShown() {
+			return m.renice(-1), nil
+		} else if k == "f8" && m.processActionsShown() {
+			return m.renice(1), nil
+		} else if k == "r" && m.processActionsShown() {
+			return m.openNicePrompt()
+		} else if k == "i" && m.processActionsShown() {
+			return m.openIOPriorityMenu(), nil
+		} else if (k == "f5" || k == "t") && m.processesTableShown() {
+			return m.toggleTreeView(), nil
+		} else if k == "c" && m.processesTableShown() {
+			return m.toggleGroupView(), nil
+		} else if k == "-" && m.processesTableShown() {
+			return m.foldProcess(true), nil
+		} else if (k == "+" || k == "=") && m.processesTableShown() {
+			return m.foldProcess(false), nil
+		} else if (k == "/" || k == "f3") && m.processesTableShown() {
+			return m.openQueryPrompt(dialogSearch, "")
+		} else if k == "n" && m.processesTableShown() {
+			return m.findMatch(m.processesTable.GetHighlightedRowIndex()+1, 1), nil
+		} else if (k == "\\" || k == "f4") && m.processesTableShown() {
+			return m.openQueryPrompt(dialogFilter, m.filter.query)
+		} else if k == "v" {
+			m.showVirtualInterfaces = !m.showVirtualInterfaces
+			m.networkTable = m.networkTable.WithRows(generateNetworkTableRows(m))
 			return m, nil
+		} else if k == "g" {
+			return m.toggleScreen(screenGraphs), nil
+		} else if k == "m" {
+			return m.toggleScreen(screenMemory), nil
+		} else if k == "C" {
+			return m.toggleScreen(screenCgroups), nil
+		} else if k == "f6" && m.processActionsShown() {
+			return m.openSortMenu(), nil
+		} else if k == "P" {
+			return m.sortProcessesBy("CpuPercentage", true), nil
+		} else if k == "M" {
+			return m.sortProcessesBy("MemPercentage", true), nil
+		} else if k == "T" {
+			return m.sortProcessesBy("CpuTime", true), nil
+		} else if k == "N" {
+			return m.sortProcessesBy("PId", false), nil
+		} else if k == "I" {
+			return m.sortProcessesBy(m.sortKey, !m.sortDesc), nil
+		} else if k == "s" {
+			return m.cycleDisksSort(), nil
+		} else if k == "S" {
+			m.disksSortDesc = !m.disksSortDesc
+			m.disksTable = sortDisksTable(m.disksTable, m)
+			return m, nil
+		} else if k == " " {
+			return m.toggleTag(), nil
 		}
 	}
 
@@ -123,8 +355,27 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	cmds = append(cmds, cmd)
 	m.disksTable, cmd = m.disksTable.Update(msg)
 	cmds = append(cmds, cmd)
-	m.processesTable, cmd = m.processesTable.Update(msg)
+	// The keys for navigating go to the table or view shown.
+	if m.screen == screenCgroups {
+		m.cgroupsTable, cmd = m.cgroupsTable.Update(msg)
+	} else if m.screen == screenProcess {
+		m.detailViewport, cmd = m.detailViewport.Update(msg)
+	} else if m.screen == screenFiles {
+		m.filesTable, cmd = m.filesTable.Update(msg)
+	} else if m.screen == screenMaps {
+		m.mapsTable, cmd = m.mapsTable.Update(msg)
+	} else {
+		m.processesTable, cmd = m.processesTable.Update(msg)
+	}
 	cmds = append(cmds, cmd)
+	// The prompts need the messages making their cursor blink.
+	if m.dialog == dialogNice {
+		m.niceInput, cmd = m.niceInput.Update(msg)
+		cmds = append(cmds, cmd)
+	} else if m.dialog.inline() {
+		m.queryInput, cmd = m.queryInput.Update(msg)
+		cmds = append(cmds, cmd)
+	}
 
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
@@ -133,40 +384,48 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.Height = msg.Height
 
 		if msg.Height < minimumHeightOneTable {
+			m.headerTable = table.New([]table.Column{})
 			m.cpuTable = table.New([]table.Column{})
 			m.memoryTable = table.New([]table.Column{})
 			m.disksTable = table.New([]table.Column{})
+			m.networkTable = table.New([]table.Column{})
 			m.processesTable = table.New([]table.Column{})
 
 			return m, tea.Batch(cmds...)
 		}
 
-		pWidth := int(float64(msg.Width) * 0.15)
+		m.cpuColumns, m.cpuGroup, m.cpuBarWidth = cpuTableLayout(len(m.CpuInfo), m.Width)
 
-		for i := range m.cpuProgresses {
-			m.cpuProgresses[i].Width = pWidth
-		}
-
-		for i := range m.memoryProgresses {
-			m.memoryProgresses[i].Width = pWidth
-		}
+		m.memoryBarWidth = int(float64(msg.Width) * 0.15)
+		m.swapProgress.Width = m.memoryBarWidth
 
 		// cpuTable will always be shown.
 		m.cpuTable = newCpuTable(m)
-		switch h := m.Height; {
+		m.cgroupsTable = newCgroupsTable(m)
+		m.detailViewport = newDetailViewport(m)
+		m.filesTable = newFilesTable(m)
+		m.mapsTable = newMapsTable(m)
+		if m.Height >= minimumHeightTwoTables {
+			m.headerTable = newHeaderTable(m)
+		}
+		switch h := m.tablesHeight(); {
 		case h >= minimumHeightTwoTables && h < minimumHeightThreeTables:
 			m.memoryTable = newMemoryTable(m)
 		case h >= minimumHeightThreeTables && h < minimumHeightAllTables:
 			m.memoryTable = newMemoryTable(m)
 			m.disksTable = newDisksTable(m)
+			m.networkTable = newNetworkTable(m)
 		case h >= minimumHeightAllTables:
-			pCount := msg.Height - minimumHeightAllTables
+			// 4 lines are kept for the actions help, the search or filter
+			// prompt and the status line.
+			pCount := h - minimumHeightAllTables - 4
 			if pCount <= 0 {
 				pCount = 2
 			}
 
 			m.memoryTable = newMemoryTable(m)
 			m.disksTable = newDisksTable(m)
+			m.networkTable = newNetworkTable(m)
 			m.processesTable = newProcessesTable(m, pCount)
 		}
 
@@ -174,20 +433,66 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// Update each table each "tick".
 	case tickMsg:
-		m.CpuInfo = getCpuInfo()
+		m.CpuAll, m.CpuInfo = getCpuInfo()
 		m.VMemoryInfo, m.SMemoryInfo = getMemoryInfo()
-		m.DisksInfo = getDiskInfo()
-		m.Processes = getProcessesInfo()
+		if m.screen == screenMemory {
+			m.MemoryDetails = getMemoryDetails()
+		}
+		if m.screen == screenCgroups {
+			m = m.updateCgroups()
+		}
+		if m.screen == screenProcess {
+			m = m.updateProcessDetails()
+		}
+		if m.screen == screenFiles {
+			m = m.updateOpenFiles()
+		}
+		if m.screen == screenMaps {
+			m = m.updateMemoryMaps()
+		}
+		m.DisksInfo = getDiskInfo(m.opts.diskFilter)
+		m.SystemInfo = getSystemInfo()
+		m.NetworkInfo = getNetworkInfo()
+		// The last processes read are kept when they can't be read again.
+		if processes, err := getProcessesInfo(m.opts.irixMode); err != nil {
+			m.status = "Failed to read the processes: " + err.Error()
+			m.statusIsError = true
+		} else {
+			m.Processes = processes
+		}
+		running := make(map[int32]struct{}, len(m.Processes))
+		for _, process := range m.Processes {
+			running[process.PId] = struct{}{}
+		}
+
+		// Tags and folds of processes that already exited are discarded.
+		for pId := range m.tagged {
+			if _, ok := running[pId]; !ok {
+				delete(m.tagged, pId)
+			}
+		}
+		for pId := range m.collapsed {
+			if _, ok := running[pId]; !ok {
+				delete(m.collapsed, pId)
+			}
+		}
+
+		m.history.record(m)
 
+		m.headerTable = m.headerTable.WithRows(generateHeaderTableRows(m))
 		m.cpuTable = m.cpuTable.WithRows(generateCpuTableRows(m))
 		m.memoryTable = m.memoryTable.WithRows(generateMemoryTableRows(m))
 		m.processesTable = m.processesTable.WithRows(generateProcessesTableRows(m))
 
+		// The disks and network tables share a row, so they are paginated
+		// together to keep the same height.
+		networkRows := generateNetworkTableRows(m)
 		var pCount int
-		if len(m.DisksInfo) > 2 {
+		if len(m.DisksInfo) > 2 || len(networkRows) > 2 {
 			pCount = 2
 		}
 		m.disksTable = m.disksTable.WithRows(generateDisksTableRows(m)).WithPageSize(pCount)
+		m.networkTable = m.networkTable.WithRows(networkRows).WithPageSize(pCount)
 
 		cmds = append(cmds, tick())
 
@@ -197,26 +502,98 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// tablesHeight returns the height of the window left for laying out the
+// tables. The minimum heights the tables are shown from count on a single row
+// of cores, so each additional row of the CPU table is subtracted.
+func (m model) tablesHeight() int {
+	bars := ceilDiv(len(m.CpuInfo), max(m.cpuGroup, 1))
+	rows := ceilDiv(bars, max(m.cpuColumns, 1))
+
+	return m.Height - max(rows-1, 0)
+}
+
+// toggleScreen shows the given screen in place of the tables, or goes back to
+// the tables when it is already shown. The data only read while its screen is
+// shown is read right away.
+func (m model) toggleScreen(screen screenKind) model {
+	if m.screen == screen {
+		m.screen = screenTables
+		return m
+	}
+
+	m.screen = screen
+	switch screen {
+	case screenMemory:
+		m.MemoryDetails = getMemoryDetails()
+	case screenCgroups:
+		// The cgroups are only collected while they are shown.
+		m.cgroupCollector.reset()
+		m = m.updateCgroups()
+	}
+
+	return m
+}
+
+// disksNetworkView renders the disks and network tables side by side.
+func (m model) disksNetworkView() string {
+	return lipgloss.JoinHorizontal(lipgloss.Top, m.disksTable.View(), m.networkTable.View())
+}
+
 // View will render the current program state from a returned string.
 func (m model) View() string {
 	var s string
 
-	if m.Height < minimumHeightOneTable {
+	// The tables take fewer lines when the CPU table takes more.
+	height := m.Height
+	if m.screen == screenTables {
+		height = m.tablesHeight()
+	}
+
+	if height < minimumHeightOneTable {
 		s = "\nWindow size is too small to show something."
 	} else {
-		s = lipgloss.NewStyle().Padding(0, 1, 1).Render(m.cpuTable.View())
-		switch h := m.Height; {
+		if height >= minimumHeightTwoTables {
+			s = lipgloss.NewStyle().Padding(0, 1).Render(m.headerTable.View()) + "\n"
+		}
+		// 1 line is kept for the help.
+		switch lines := m.Height - strings.Count(s, "\n") - 1; m.screen {
+		case screenGraphs:
+			return s + m.graphsView(lines) + "\n g or Esc to go back to the tables."
+		case screenMemory:
+			return s + m.memoryDetailsView(lines) + "\n m or Esc to go back to the tables."
+		case screenCgroups:
+			return s + m.cgroupsView() + "\n ↑ / ↓ / ← / → for navigation, C or Esc to go back to the tables."
+		case screenProcess:
+			return s + m.processDetailsView() +
+				"\n ↑ / ↓ / PgUp / PgDn to scroll, o for the open files, M for the memory maps, F9/k to send a signal, F7/F8 to renice, r to set the nice value, i for the I/O priority, Enter or Esc to go back to the tables." +
+				m.statusView()
+		case screenFiles:
+			return s + m.filesView() + "\n ↑ / ↓ / ← / → for navigation, \\ to filter, o or Esc to go back to the process."
+		case screenMaps:
+			return s + m.mapsView() + "\n ↑ / ↓ / ← / → for navigation, s/S to sort, f to group by file, M or Esc to go back to the process."
+		}
+
+		s += lipgloss.NewStyle().Padding(0, 1, 1).Render(m.cpuTable.View())
+		switch h := height; {
 		case h >= minimumHeightTwoTables && h < minimumHeightThreeTables:
 			s += lipgloss.NewStyle().Padding(1).Render(m.memoryTable.View())
 		case h >= minimumHeightThreeTables && h < minimumHeightAllTables:
 			s += lipgloss.NewStyle().Padding(1).Render(m.memoryTable.View())
-			s += lipgloss.NewStyle().Padding(1).Render(m.disksTable.View())
-			s += "\n a/d for the disks table navigation."
+			s += lipgloss.NewStyle().Padding(1).Render(m.disksNetworkView())
+			s += "\n a/d for the disks and network tables navigation, s/S to sort the disks, v to show virtual interfaces."
 		case h >= minimumHeightAllTables:
 			s += lipgloss.NewStyle().Padding(1).Render(m.memoryTable.View())
-			s += lipgloss.NewStyle().Padding(1).Render(m.disksTable.View())
-			s += lipgloss.NewStyle().Padding(1).Render(m.processesTable.View())
-			s += "\n a/d for the disks table, ↑ / ↓ / ← / → for processes table navigation."
+			s += lipgloss.NewStyle().Padding(1).Render(m.disksNetworkView())
+			if m.dialog != dialogNone && !m.dialog.inline() {
+				s += lipgloss.NewStyle().Padding(1).Render(m.dialogView())
+			} else {
+				s += lipgloss.NewStyle().Padding(1).Render(m.processesTable.View())
+			}
+			s += m.queryView()
+			s += "\n a/d for the disks and network tables (s/S to sort the disks, v to show virtual interfaces), ↑ / ↓ / ← / → for processes table navigation, F6 to sort it (P/M/T/N, I to invert)."
+			s += "\n Enter for the details of a process, space to tag it, F9/k to send a signal, F7/F8 to renice, r to set the nice value, i for the I/O priority."
+			s += "\n F5/t to toggle the tree view, - / + to fold or unfold a subtree, / to search (n for the next match), \\ to filter, c to group by container, g for the graphs, m for the memory details, C for the cgroups."
+			s += m.statusView()
 		}
 	}
 